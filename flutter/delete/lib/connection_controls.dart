@@ -44,6 +44,12 @@ class _ConnectionControlsState extends State<ConnectionControls> {
     state.database = value;
   }
 
+  void authTypeChanged(String value) {
+    setState(() {
+      state.authType = value;
+    });
+  }
+
   void toggleUrlCollapse() {
     setState(() {
       state.urlCollapsed = !state.urlCollapsed;
@@ -75,6 +81,19 @@ class _ConnectionControlsState extends State<ConnectionControls> {
                 TextField(controller: this.userController, decoration: InputDecoration(labelText: "username"), onChanged: usernameChanged, autocorrect: false),
                 TextField(controller: this.passwordController, decoration: InputDecoration(labelText: "password"), autocorrect: false, obscureText: true, onChanged: passwordChanged),
                 TextField(controller: this.databaseController, decoration: InputDecoration(labelText: "database"), onChanged: databaseChanged, autocorrect: false),
+                SizedBox(height: 20),
+                Text("authentication:", overflow: TextOverflow.ellipsis, textScaleFactor: 0.9),
+                DropdownButton<String>(
+                  hint: Text("authentication", overflow: TextOverflow.ellipsis),
+                  items: [
+                    DropdownMenuItem(child: Text("Basic", overflow: TextOverflow.ellipsis), value: ""),
+                    DropdownMenuItem(child: Text("Bearer", overflow: TextOverflow.ellipsis), value: "Bearer"),
+                    DropdownMenuItem(child: Text("Kerberos", overflow: TextOverflow.ellipsis), value: "Kerberos"),
+                    DropdownMenuItem(child: Text("None", overflow: TextOverflow.ellipsis), value: "None"),
+                  ],
+                  value: state.authType == 'Basic' ? '' : state.authType,
+                  onChanged: authTypeChanged,
+                ),
               ],
             ),
           );
@@ -15,7 +15,7 @@ class AyxToNeo4jMap {
 typedef List<String> LazyFieldLoader();
 
 class Configuration extends BlocState {
-  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.deleteObject, this.batchSize, this.nodeLabel, this.nodeIdFields, this.relType, this.relFields, this.relLeftLabel, this.relLeftFields, this.relRightLabel, this.relRightFields, this.loadFields});
+  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.deleteObject, this.batchSize, this.nodeLabel, this.nodeIdFields, this.relType, this.relFields, this.relLeftLabel, this.relLeftFields, this.relRightLabel, this.relRightFields, this.authType, this.dryRun, this.loadFields, this.original});
 
   String connStr;
   String username;
@@ -32,7 +32,10 @@ class Configuration extends BlocState {
   List<AyxToNeo4jMap> relLeftFields;
   String relRightLabel;
   List<AyxToNeo4jMap> relRightFields;
+  String authType;
+  bool dryRun;
   LazyFieldLoader loadFields;
+  Map original;
 
   bool _decrypting = false;
   String _decrypted;
@@ -83,7 +86,8 @@ class Configuration extends BlocState {
   }
 
   Map toJson() {
-    return {
+    var json = Map.from(original ?? {});
+    json.addAll({
       "ConnStr": connStr,
       "Username": username,
       "Password": password,
@@ -99,7 +103,10 @@ class Configuration extends BlocState {
       "RelLeftFields": relLeftFields.map<Map>((e) => e.toJson()).toList(),
       "RelRightLabel": relRightLabel,
       "RelRightFields": relRightFields.map<Map>((e) => e.toJson()).toList(),
-    };
+      "AuthType": authType ?? '',
+      "DryRun": dryRun ?? false,
+    });
+    return json;
   }
 }
 
@@ -121,6 +128,8 @@ Configuration decodeConfig(String configStr, LazyFieldLoader incomingFields) {
       relLeftFields: [],
       relRightLabel: '',
       relRightFields: [],
+      authType: '',
+      dryRun: false,
       loadFields: incomingFields,
     );
   }
@@ -141,7 +150,10 @@ Configuration decodeConfig(String configStr, LazyFieldLoader incomingFields) {
     relLeftFields: decodeFieldMapping(decoded['RelLeftFields']),
     relRightLabel: decoded['RelRightLabel'] ?? '',
     relRightFields: decodeFieldMapping(decoded['RelRightFields']),
+    authType: decoded['AuthType'] ?? '',
+    dryRun: decoded['DryRun'] ?? false,
     loadFields: incomingFields,
+    original: decoded,
   );
 }
 
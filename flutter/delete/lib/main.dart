@@ -72,6 +72,12 @@ class _ControlsState extends State<Controls> {
     config.batchSize = intValue;
   }
 
+  void dryRunChanged (bool value) {
+    setState(() {
+      config.dryRun = value;
+    });
+  }
+
   void initState() {
     config = BlocProvider.of<Configuration>(context);
     batchSizeController = TextEditingController(text: config.batchSize.toString());
@@ -92,6 +98,11 @@ class _ControlsState extends State<Controls> {
               children: [
                 TextField(controller: batchSizeController, decoration: InputDecoration(labelText: "batch  size"), onChanged: batchSizeChanged, inputFormatters: [FilteringTextInputFormatter.allow(RegExp(r'[0-9]'))]),
                 ExportObjectSelector(()=>setState((){})),
+                CheckboxListTile(
+                  title: Text("dry run (explain the query without deleting)", overflow: TextOverflow.ellipsis),
+                  value: config.dryRun ?? false,
+                  onChanged: dryRunChanged,
+                ),
               ],
             ),
           ),
@@ -75,4 +75,20 @@ void main() {
     var jsonString = json.encode(jsonObj);
     print(jsonString);
   });
-}
\ No newline at end of file
+
+  test("keep settings the GUI does not edit", (){
+    var configStr = '{"ConnStr": "bolt://localhost:7687", "DeleteObject": "Node", "BatchSize": 500, "NodeLabel": "TestNode", "NodeIdFields": ["Node1"], "AuthType": "Bearer", "DryRun": true, "RetryPolicy": {"MaxAttempts": 3}, "TlsSkipVerify": true, "CredentialProvider": "Environment"}';
+    var decoded = decodeConfig(configStr, () => []);
+    expect(decoded.authType, equals('Bearer'));
+    expect(decoded.dryRun, equals(true));
+    decoded.authType = 'None';
+    decoded.dryRun = false;
+
+    var encoded = decoded.toJson();
+    expect(encoded['AuthType'], equals('None'));
+    expect(encoded['DryRun'], equals(false));
+    expect(encoded['RetryPolicy'], equals({'MaxAttempts': 3}));
+    expect(encoded['TlsSkipVerify'], equals(true));
+    expect(encoded['CredentialProvider'], equals('Environment'));
+  });
+}
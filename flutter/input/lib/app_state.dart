@@ -13,7 +13,7 @@ enum updated {
 }
 
 class Configuration {
-  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.query, this.lastValidatedResponse, this.fields});
+  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.query, this.lastValidatedResponse, this.fields, this.original});
   String connStr;
   String username;
   String password;
@@ -22,9 +22,11 @@ class Configuration {
   String query;
   ValidatedResponse lastValidatedResponse;
   List<Field> fields;
+  Map original;
 
   Map toJson(){
-    return {
+    var json = Map.from(original ?? {});
+    json.addAll({
       'ConnStr': connStr,
       'Username': username,
       'Password': password,
@@ -33,22 +35,33 @@ class Configuration {
       'UrlCollapsed': urlCollapsed,
       'LastValidatedResponse': lastValidatedResponse.toJson(),
       'Fields': fields.map((e) => e.toJson()).toList(),
-    };
+    });
+    return json;
   }
 }
 
 class Field {
-  Field({this.name, this.dataType, this.path});
+  Field({this.name, this.dataType, this.path, this.decimalOptions, this.original});
   String name;
   String dataType;
   List<PathElement> path;
+  Map decimalOptions;
+  Map original;
 
   Map toJson(){
-    return {
+    var json = Map.from(original ?? {});
+    for (var key in ['Size', 'Scale', 'ScaledInteger']) {
+      json.remove(key);
+    }
+    json.addAll({
       'Name': name,
       'DataType': dataType,
       'Path': path.map((e) => {'Key': e.key, 'DataType': e.dataType}).toList(),
-    };
+    });
+    if (decimalOptions != null && dataType == 'FixedDecimal') {
+      json.addAll(decimalOptions);
+    }
+    return json;
   }
 }
 
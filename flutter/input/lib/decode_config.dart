@@ -27,6 +27,7 @@ Configuration decodeConfig(String configStr) {
     query: decoded['Query'] ?? '',
     lastValidatedResponse: _decodeValidatedResponse(decoded['LastValidatedResponse']),
     fields: _decodeFields(decoded['Fields']),
+    original: decoded,
   );
 }
 
@@ -70,11 +71,23 @@ List<Field> _decodeFields(dynamic fieldsArray) {
       name: field['Name'] ?? '',
       dataType: field['DataType'] ?? '',
       path: _decodePath(field['Path']),
+      decimalOptions: _decodeDecimalOptions(field),
+      original: field,
     ));
   }
   return fields;
 }
 
+Map _decodeDecimalOptions(dynamic field) {
+  var options = {};
+  for (var key in ['Size', 'Scale', 'ScaledInteger']) {
+    if (field[key] != null) {
+      options[key] = field[key];
+    }
+  }
+  return options;
+}
+
 List<PathElement> _decodePath(dynamic elementArray) {
   if (elementArray == null) {
     return [];
@@ -38,6 +38,10 @@ class PathSelector extends StatelessWidget {
             return SelectRelationshipChild(field);
           case 'Map':
             return SelectMapChild(field);
+          case 'Point':
+            return SelectPointChild(field);
+          case 'Duration':
+            return SelectDurationChild(field);
           case 'Integer':
           case 'Float':
           case 'DateTime':
@@ -210,6 +214,7 @@ class SelectNodeChild extends StatelessWidget {
     return DropDown<SelectData>(
       items: [
         DropdownMenuItem<SelectData>(child: Text("ID", overflow: TextOverflow.ellipsis), value: SelectData("ID", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("ElementId", overflow: TextOverflow.ellipsis), value: SelectData("ElementId", 'String')),
         DropdownMenuItem<SelectData>(child: Text("Labels", overflow: TextOverflow.ellipsis), value: SelectData("Labels", 'List:String')),
         DropdownMenuItem<SelectData>(child: Text("Properties", overflow: TextOverflow.ellipsis), value: SelectData("Properties", 'Map')),
         DropdownMenuItem<SelectData>(child: Text("To String", overflow: TextOverflow.ellipsis), value: SelectData("ToString", 'String')),
@@ -232,6 +237,9 @@ class SelectRelationshipChild extends StatelessWidget {
         DropdownMenuItem<SelectData>(child: Text("ID", overflow: TextOverflow.ellipsis), value: SelectData("ID", 'Integer')),
         DropdownMenuItem<SelectData>(child: Text("StartId", overflow: TextOverflow.ellipsis), value: SelectData("StartId", 'Integer')),
         DropdownMenuItem<SelectData>(child: Text("EndId", overflow: TextOverflow.ellipsis), value: SelectData("EndId", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("ElementId", overflow: TextOverflow.ellipsis), value: SelectData("ElementId", 'String')),
+        DropdownMenuItem<SelectData>(child: Text("StartElementId", overflow: TextOverflow.ellipsis), value: SelectData("StartElementId", 'String')),
+        DropdownMenuItem<SelectData>(child: Text("EndElementId", overflow: TextOverflow.ellipsis), value: SelectData("EndElementId", 'String')),
         DropdownMenuItem<SelectData>(child: Text("Type", overflow: TextOverflow.ellipsis), value: SelectData("Type", 'String')),
         DropdownMenuItem<SelectData>(child: Text("Properties", overflow: TextOverflow.ellipsis), value: SelectData("Properties", 'Map')),
         DropdownMenuItem<SelectData>(child: Text("To String", overflow: TextOverflow.ellipsis), value: SelectData("ToString", 'String')),
@@ -244,6 +252,50 @@ class SelectRelationshipChild extends StatelessWidget {
   }
 }
 
+class SelectPointChild extends StatelessWidget {
+  SelectPointChild(this.field);
+  final Field field;
+
+  Widget build(BuildContext context) {
+    return DropDown<SelectData>(
+      items: [
+        DropdownMenuItem<SelectData>(child: Text("X", overflow: TextOverflow.ellipsis), value: SelectData("X", 'Float')),
+        DropdownMenuItem<SelectData>(child: Text("Y", overflow: TextOverflow.ellipsis), value: SelectData("Y", 'Float')),
+        DropdownMenuItem<SelectData>(child: Text("Z", overflow: TextOverflow.ellipsis), value: SelectData("Z", 'Float')),
+        DropdownMenuItem<SelectData>(child: Text("SRID", overflow: TextOverflow.ellipsis), value: SelectData("SRID", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("To WKT", overflow: TextOverflow.ellipsis), value: SelectData("ToWKT", 'String')),
+        DropdownMenuItem<SelectData>(child: Text("To GeoJSON", overflow: TextOverflow.ellipsis), value: SelectData("ToGeoJSON", 'String')),
+      ],
+      onChanged: (e){
+        var fieldState = BlocProvider.of<FieldState>(context);
+        fieldState.addElementToPath(PathElement(key: e.name, dataType: e.dataType));
+      },
+    );
+  }
+}
+
+class SelectDurationChild extends StatelessWidget {
+  SelectDurationChild(this.field);
+  final Field field;
+
+  Widget build(BuildContext context) {
+    return DropDown<SelectData>(
+      items: [
+        DropdownMenuItem<SelectData>(child: Text("Months", overflow: TextOverflow.ellipsis), value: SelectData("Months", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("Days", overflow: TextOverflow.ellipsis), value: SelectData("Days", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("Seconds", overflow: TextOverflow.ellipsis), value: SelectData("Seconds", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("Nanos", overflow: TextOverflow.ellipsis), value: SelectData("Nanos", 'Integer')),
+        DropdownMenuItem<SelectData>(child: Text("TotalSeconds", overflow: TextOverflow.ellipsis), value: SelectData("TotalSeconds", 'Float')),
+        DropdownMenuItem<SelectData>(child: Text("To String", overflow: TextOverflow.ellipsis), value: SelectData("ToString", 'String')),
+      ],
+      onChanged: (e){
+        var fieldState = BlocProvider.of<FieldState>(context);
+        fieldState.addElementToPath(PathElement(key: e.name, dataType: e.dataType));
+      },
+    );
+  }
+}
+
 class SelectMapChild extends StatefulWidget {
   SelectMapChild(this.field);
   final Field field;
@@ -275,8 +327,10 @@ class _SelectMapChildState extends State<SelectMapChild>{
             items: [
               DropdownMenuItem<String>(child: Text("Boolean", overflow: TextOverflow.ellipsis), value: "Boolean"),
               DropdownMenuItem<String>(child: Text("DateTime", overflow: TextOverflow.ellipsis), value: "DateTime"),
+              DropdownMenuItem<String>(child: Text("Duration", overflow: TextOverflow.ellipsis), value: "Duration"),
               DropdownMenuItem<String>(child: Text("Float", overflow: TextOverflow.ellipsis), value: "Float"),
               DropdownMenuItem<String>(child: Text("Integer", overflow: TextOverflow.ellipsis), value: "Integer"),
+              DropdownMenuItem<String>(child: Text("Point", overflow: TextOverflow.ellipsis), value: "Point"),
               DropdownMenuItem<String>(child: Text("String", overflow: TextOverflow.ellipsis), value: "String"),
             ],
             onChanged: (e){
@@ -7,5 +7,7 @@ const String rRelationship = 'Relationship';
 const String rMap = 'Map';
 const String rBoolean = 'Boolean';
 const String rFloat = 'Float';
+const String rPoint = 'Point';
+const String rDuration = 'Duration';
 const String rUnknown = 'Unknown';
 const String rList = 'List';
\ No newline at end of file
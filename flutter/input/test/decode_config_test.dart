@@ -62,4 +62,18 @@ void main(){
     expect(decoded.lastValidatedResponse.returnValues.length, equals(1));
     print(json.encode(decoded.toJson()));
   });
-}
\ No newline at end of file
+
+  test('keep settings the GUI does not edit', (){
+    var configStr = '{"ConnStr": "bolt://localhost:7687", "Query": "MATCH (n) RETURN n", "ParamMode": "Batch", "PageMode": "Skip", "PageSize": 1000, "ZoneColumns": "Offset", "Fields": [{"Name": "Field1", "DataType": "String", "Path": [{"Key": "n", "DataType": "Node"}], "ListMode": "Json"}]}';
+    var decoded = decodeConfig(configStr);
+    decoded.query = 'MATCH (m) RETURN m';
+
+    var encoded = decoded.toJson();
+    expect(encoded['Query'], equals('MATCH (m) RETURN m'));
+    expect(encoded['ParamMode'], equals('Batch'));
+    expect(encoded['PageMode'], equals('Skip'));
+    expect(encoded['PageSize'], equals(1000));
+    expect(encoded['ZoneColumns'], equals('Offset'));
+    expect(encoded['Fields'][0]['ListMode'], equals('Json'));
+  });
+}
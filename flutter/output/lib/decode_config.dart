@@ -15,7 +15,7 @@ class AyxToNeo4jMap {
 typedef List<String> LazyFieldLoader();
 
 class Configuration extends BlocState {
-  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.exportObject, this.batchSize, this.nodeLabel, this.nodeIdFields, this.nodePropFields, this.relLabel, this.relIdFields, this.relPropFields, this.relLeftLabel, this.relLeftFields, this.relRightLabel, this.relRightFields, this.loadFields});
+  Configuration({this.connStr, this.username, this.password, this.database, this.urlCollapsed, this.exportObject, this.batchSize, this.nodeLabel, this.nodeIdFields, this.nodePropFields, this.relLabel, this.relIdFields, this.relPropFields, this.relLeftLabel, this.relLeftFields, this.relRightLabel, this.relRightFields, this.nodePropertyNames, this.relPropertyNames, this.loadFields, this.original});
 
   String connStr;
   String username;
@@ -34,7 +34,10 @@ class Configuration extends BlocState {
   List<AyxToNeo4jMap> relLeftFields;
   String relRightLabel;
   List<AyxToNeo4jMap> relRightFields;
+  Map<String, String> nodePropertyNames;
+  Map<String, String> relPropertyNames;
   LazyFieldLoader loadFields;
+  Map original;
 
   bool _decrypting = false;
   String _decrypted;
@@ -85,7 +88,8 @@ class Configuration extends BlocState {
   }
 
   Map toJson() {
-    return {
+    var json = Map.from(original ?? {});
+    json.addAll({
       "ConnStr": connStr,
       "Username": username,
       "Password": password,
@@ -94,16 +98,17 @@ class Configuration extends BlocState {
       "ExportObject": exportObject,
       "BatchSize": batchSize,
       "NodeLabel": nodeLabel,
-      "NodeIdFields": nodeIdFields,
-      "NodePropFields": nodePropFields,
+      "NodeIdFields": encodeMappedFieldList(nodeIdFields, nodePropertyNames),
+      "NodePropFields": encodeMappedFieldList(nodePropFields, nodePropertyNames),
       "RelLabel": relLabel,
-      "RelIdFields": relIdFields,
-      "RelPropFields": relPropFields,
+      "RelIdFields": encodeMappedFieldList(relIdFields, relPropertyNames),
+      "RelPropFields": encodeMappedFieldList(relPropFields, relPropertyNames),
       "RelLeftLabel": relLeftLabel,
       "RelLeftFields": relLeftFields.map<Map>((e) => e.toJson()).toList(),
       "RelRightLabel": relRightLabel,
       "RelRightFields": relRightFields.map<Map>((e) => e.toJson()).toList(),
-    };
+    });
+    return json;
   }
 }
 
@@ -127,10 +132,14 @@ Configuration decodeConfig(String configStr, LazyFieldLoader incomingFields) {
       relLeftFields: [],
       relRightLabel: '',
       relRightFields: [],
+      nodePropertyNames: {},
+      relPropertyNames: {},
       loadFields: incomingFields,
     );
   }
   var decoded = json.decode(configStr);
+  Map<String, String> nodePropertyNames = {};
+  Map<String, String> relPropertyNames = {};
   return Configuration(
     connStr: decoded['ConnStr'] ?? '',
     username: decoded['Username'] ?? '',
@@ -140,16 +149,19 @@ Configuration decodeConfig(String configStr, LazyFieldLoader incomingFields) {
     exportObject: decoded['ExportObject'] ?? 'Node',
     batchSize: decoded['BatchSize'] ?? 10000,
     nodeLabel: decoded['NodeLabel'] ?? '',
-    nodeIdFields: decodeStringList(decoded['NodeIdFields']),
-    nodePropFields: decodeStringList(decoded['NodePropFields']),
+    nodeIdFields: decodeMappedFieldList(decoded['NodeIdFields'], nodePropertyNames),
+    nodePropFields: decodeMappedFieldList(decoded['NodePropFields'], nodePropertyNames),
     relLabel: decoded['RelLabel'] ?? '',
-    relIdFields: decodeStringList(decoded['RelIdFields']),
-    relPropFields: decodeStringList(decoded['RelPropFields']),
+    relIdFields: decodeMappedFieldList(decoded['RelIdFields'], relPropertyNames),
+    relPropFields: decodeMappedFieldList(decoded['RelPropFields'], relPropertyNames),
     relLeftLabel: decoded['RelLeftLabel'] ?? '',
     relLeftFields: decodeFieldMapping(decoded['RelLeftFields']),
     relRightLabel: decoded['RelRightLabel'] ?? '',
     relRightFields: decodeFieldMapping(decoded['RelRightFields']),
+    nodePropertyNames: nodePropertyNames,
+    relPropertyNames: relPropertyNames,
     loadFields: incomingFields,
+    original: decoded,
   );
 }
 
@@ -164,6 +176,34 @@ List<String> decodeStringList(dynamic strings) {
   return list;
 }
 
+List<String> decodeMappedFieldList(dynamic entries, Map<String, String> propertyNames) {
+  List<String> list = [];
+  if (entries == null) {
+    return list;
+  }
+  for (var entry in entries) {
+    if (entry is Map) {
+      for (var mapping in entry.entries) {
+        list.add(mapping.key.toString());
+        propertyNames[mapping.key.toString()] = mapping.value.toString();
+      }
+      continue;
+    }
+    list.add(entry.toString());
+  }
+  return list;
+}
+
+List<dynamic> encodeMappedFieldList(List<String> fields, Map<String, String> propertyNames) {
+  return fields.map<dynamic>((field) {
+    var propertyName = propertyNames == null ? null : propertyNames[field];
+    if (propertyName == null) {
+      return field;
+    }
+    return {field: propertyName};
+  }).toList();
+}
+
 List<AyxToNeo4jMap> decodeFieldMapping(dynamic jsonItem) {
   List<AyxToNeo4jMap> fieldMap = [];
   if (jsonItem == null) {
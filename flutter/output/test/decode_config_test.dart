@@ -34,6 +34,20 @@ void main() {
     expect(decoded.relRightFields[1].neo4jField, equals('Neo4jField3'));
   });
 
+  test('mapped property fields survive decoding and encoding',(){
+    var configStr = '{"ExportObject":"Node","NodeIdFields":[{"Cust ID":"customerId"}],"NodePropFields":["Name",{"Cust Region":"region"}],"RelIdFields":[],"RelPropFields":[{"Since":"since"}],"RelLeftFields":[],"RelRightFields":[]}';
+    var decoded = decodeConfig(configStr, ()=>[]);
+
+    expect(decoded.nodeIdFields, equals(["Cust ID"]));
+    expect(decoded.nodePropFields, equals(["Name","Cust Region"]));
+    expect(decoded.relPropFields, equals(["Since"]));
+
+    var encoded = decoded.toJson();
+    expect(encoded['NodeIdFields'], equals([{"Cust ID":"customerId"}]));
+    expect(encoded['NodePropFields'], equals(["Name",{"Cust Region":"region"}]));
+    expect(encoded['RelPropFields'], equals([{"Since":"since"}]));
+  });
+
   test('instantiate empty config',(){
     var configStr = '';
     var decoded = decodeConfig(configStr, ()=>[]);
@@ -81,4 +95,17 @@ void main() {
     var jsonString = json.encode(jsonObj);
     print(jsonString);
   });
-}
\ No newline at end of file
+
+  test("keep settings the GUI does not edit", (){
+    var configStr = '{"ConnStr": "bolt://localhost:7687", "ExportObject": "Node", "BatchSize": 500, "NodeLabel": "TestNode", "NodeIdFields": ["Node1"], "NodePropFields": [], "WriteMode": "InsertOnly", "Writers": 4, "RetryPolicy": {"MaxAttempts": 3}, "Checkpoint": "File"}';
+    var decoded = decodeConfig(configStr, () => []);
+    decoded.nodeLabel = 'Renamed';
+
+    var encoded = decoded.toJson();
+    expect(encoded['NodeLabel'], equals('Renamed'));
+    expect(encoded['WriteMode'], equals('InsertOnly'));
+    expect(encoded['Writers'], equals(4));
+    expect(encoded['RetryPolicy'], equals({'MaxAttempts': 3}));
+    expect(encoded['Checkpoint'], equals('File'));
+  });
+}
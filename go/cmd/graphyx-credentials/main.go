@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/tlarsendataguy/graphyx/util"
+	"os"
+	"strings"
+)
+
+func main() {
+	path := flag.String(`file`, os.Getenv(`GRAPHYX_CREDENTIAL_FILE`), `the credential file to create or update`)
+	url := flag.String(`url`, ``, `the Neo4j url the credential is for, exactly as entered in the tool`)
+	username := flag.String(`username`, ``, `the Neo4j username`)
+	flag.Parse()
+
+	key := os.Getenv(`GRAPHYX_CREDENTIAL_KEY`)
+	if *path == `` || *url == `` || key == `` {
+		fmt.Fprintln(os.Stderr, `usage: set GRAPHYX_CREDENTIAL_KEY to the passphrase, then run graphyx-credentials -file <path> -url <url> -username <username> and enter the password on stdin`)
+		os.Exit(2)
+	}
+
+	fmt.Fprint(os.Stderr, `password: `)
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == `` {
+		fmt.Fprintf(os.Stderr, "error reading the password: %v\n", err.Error())
+		os.Exit(1)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	err = util.SaveCredentialToFile(*path, key, *url, util.StoredCredential{Username: *username, Password: password})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "saved the credential for %v to %v\n", *url, *path)
+}
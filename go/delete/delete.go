@@ -1,10 +1,11 @@
 package delete
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"github.com/tlarsendataguy/graphyx/util"
 )
@@ -14,20 +15,33 @@ type xmlConfig struct {
 }
 
 type Configuration struct {
-	ConnStr        string
-	Username       string
-	Password       string
-	Database       string
-	DeleteObject   string
-	BatchSize      int
-	NodeLabel      string
-	NodeIdFields   []string
-	RelType        string
-	RelFields      []string
-	RelLeftLabel   string
-	RelLeftFields  []map[string]interface{}
-	RelRightLabel  string
-	RelRightFields []map[string]interface{}
+	ConnStr                             string
+	Username                            string
+	Password                            string
+	Database                            string
+	DeleteObject                        string
+	BatchSize                           int
+	NodeLabel                           string
+	NodeIdFields                        []string
+	RelType                             string
+	RelFields                           []string
+	RelLeftLabel                        string
+	RelLeftFields                       []map[string]interface{}
+	RelRightLabel                       string
+	RelRightFields                      []map[string]interface{}
+	CredentialProvider                  string
+	CredentialFile                      string
+	CredentialPrecedence                string
+	AuthType                            string
+	Realm                               string
+	TlsCaFile                           string
+	TlsSkipVerify                       bool
+	MaxConnectionPoolSize               int
+	MaxTransactionRetrySeconds          int
+	ConnectionAcquisitionTimeoutSeconds int
+	UserAgent                           string
+	DryRun                              bool
+	RetryPolicy                         util.RetryPolicy
 }
 
 type Neo4jDelete struct {
@@ -37,14 +51,18 @@ type Neo4jDelete struct {
 	query            string
 	copiers          []util.CopyData
 	requiredFields   []string
-	driver           neo4j.Driver
-	session          neo4j.Session
+	ctx              context.Context
+	driver           neo4j.DriverWithContext
+	session          neo4j.SessionWithContext
 	batch            []map[string]interface{}
 	currentBatchSize int
+	explained        bool
+	dryRunRecords    int
 }
 
 func (d *Neo4jDelete) Init(provider sdk.Provider) {
 	d.provider = provider
+	d.ctx = context.Background()
 	var rawConfig xmlConfig
 	err := xml.Unmarshal([]byte(provider.ToolConfig()), &rawConfig)
 	if err != nil {
@@ -115,24 +133,18 @@ func (d *Neo4jDelete) OnInputConnectionOpened(connection sdk.InputConnection) {
 	for _, field := range d.requiredFields {
 		copier, err = util.FindFieldAndGenerateCopier(field, incomingInfo)
 		if err != nil {
-			d.error(fmt.Sprintf(`field %v was not contained in the record`, field))
+			d.error(err.Error())
 			return
 		}
 		d.copiers = append(d.copiers, copier)
 	}
 
-	username, password := util.GetCredentials(d.config.ConnStr, d.config.Username, d.config.Password, d.provider)
-	d.driver, err = neo4j.NewDriver(d.config.ConnStr, neo4j.BasicAuth(username, password, ""))
+	d.driver, err = util.AcquireDriver(d.ctx, d.connectionConfig(), d.provider)
 	if err != nil {
 		d.error(err.Error())
 		return
 	}
-	err = d.driver.VerifyConnectivity()
-	if err != nil {
-		d.error(err.Error())
-		return
-	}
-	d.session = d.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: d.config.Database})
+	d.session = d.driver.NewSession(d.ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: d.config.Database})
 }
 
 func (d *Neo4jDelete) OnRecordPacket(connection sdk.InputConnection) {
@@ -144,6 +156,9 @@ func (d *Neo4jDelete) OnRecordPacket(connection sdk.InputConnection) {
 	for packet.Next() {
 		if d.currentBatchSize >= d.config.BatchSize {
 			d.sendBatch()
+			if !d.doExport {
+				return
+			}
 		}
 		copyFrom := packet.Record()
 		copyTo := d.batch[d.currentBatchSize]
@@ -160,26 +175,79 @@ func (d *Neo4jDelete) OnComplete() {
 		d.sendBatch()
 	}
 	if d.session != nil {
-		_ = d.session.Close()
+		_ = d.session.Close(d.ctx)
 	}
 	if d.driver != nil {
-		_ = d.driver.Close()
+		_ = util.ReleaseDriver(d.ctx, d.driver)
+	}
+	if d.config.DryRun && d.doExport {
+		d.provider.Io().Info(fmt.Sprintf(`Dry run complete: %v records were checked and nothing was deleted`, d.dryRunRecords))
 	}
 	d.provider.Io().UpdateProgress(1.0)
 }
 
 func (d *Neo4jDelete) sendBatch() {
-	_, err := d.session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		return tx.Run(d.query, map[string]interface{}{`batch`: d.batch[:d.currentBatchSize]})
+	if d.config.DryRun {
+		d.explainBatch()
+		return
+	}
+	failures := d.config.RetryPolicy.Run(d.ctx, d.batch[:d.currentBatchSize], d.deleteRows)
+	if len(failures) > 0 {
+		d.error(failures[0].Err.Error())
+		return
+	}
+	d.currentBatchSize = 0
+}
+
+func (d *Neo4jDelete) deleteRows(rows []map[string]interface{}) error {
+	_, err := d.session.ExecuteWrite(d.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(d.ctx, d.query, map[string]interface{}{`batch`: rows})
+		if txErr != nil {
+			return nil, txErr
+		}
+		return result.Consume(d.ctx)
 	})
+	return err
+}
+
+func (d *Neo4jDelete) explainBatch() {
+	rows := d.batch[:d.currentBatchSize]
+	d.dryRunRecords += len(rows)
+	d.currentBatchSize = 0
+	if d.explained {
+		return
+	}
+	d.explained = true
+	explained, err := util.Explain(d.ctx, d.session, d.query, map[string]interface{}{`batch`: rows})
 	if err != nil {
-		d.error(err.Error())
+		d.error(fmt.Sprintf("dry run failed for query:\n%v\n%v", d.query, err.Error()))
 		return
 	}
-	d.currentBatchSize = 0
+	explained.Report(d.provider.Io())
 }
 
 func (d *Neo4jDelete) error(msg string) {
 	d.provider.Io().Error(msg)
 	d.doExport = false
 }
+
+func (d *Neo4jDelete) connectionConfig() util.ConnectionConfig {
+	return util.ConnectionConfig{
+		Credentials: util.CredentialSource{
+			Url:        d.config.ConnStr,
+			Username:   d.config.Username,
+			Password:   d.config.Password,
+			Provider:   d.config.CredentialProvider,
+			Precedence: d.config.CredentialPrecedence,
+			File:       d.config.CredentialFile,
+			AuthType:   d.config.AuthType,
+			Realm:      d.config.Realm,
+		},
+		TlsCaFile:                           d.config.TlsCaFile,
+		TlsSkipVerify:                       d.config.TlsSkipVerify,
+		MaxConnectionPoolSize:               d.config.MaxConnectionPoolSize,
+		MaxTransactionRetrySeconds:          d.config.MaxTransactionRetrySeconds,
+		ConnectionAcquisitionTimeoutSeconds: d.config.ConnectionAcquisitionTimeoutSeconds,
+		UserAgent:                           d.config.UserAgent,
+	}
+}
@@ -2,7 +2,7 @@ module github.com/tlarsendataguy/graphyx
 
 go 1.19
 
-require github.com/neo4j/neo4j-go-driver/v4 v4.4.7
+require github.com/neo4j/neo4j-go-driver/v5 v5.28.4
 
 require (
 	github.com/danieljoos/wincred v1.2.0 // indirect
@@ -1,12 +1,16 @@
 package main_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"github.com/tlarsendataguy/graphyx/delete"
 	"github.com/tlarsendataguy/graphyx/input"
 	"github.com/tlarsendataguy/graphyx/output"
+	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"testing"
@@ -76,6 +80,119 @@ func TestOptionalRelationshipMatch(t *testing.T) {
 	t.Logf(`%v`, collector.Data)
 }
 
+func TestInputWithRecordParams(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Query":"MATCH (m:Movie {title:$Title}) RETURN m.title AS title","Database":"neo4j","ParamMode":"Record","ParamFields":["Title"],"Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}]}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jInputParams.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Movie`]); rows != 2 {
+		t.Fatalf(`expected 2 rows but got %v`, rows)
+	}
+	t.Logf(`%v`, collector.Data)
+}
+
+func TestInputSkipsParamsThatCannotBeCopied(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Query":"MATCH (m:Movie {title:$Title}) RETURN m.title AS title","Database":"neo4j","ParamMode":"Record","ParamFields":["Title","Tags"],"Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}]}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jInputBadParams.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Movie`]); rows != 1 {
+		t.Fatalf(`expected 1 row but got %v`, rows)
+	}
+	if title := collector.Data[`Movie`][0]; title != `Apollo 13` {
+		t.Fatalf(`expected 'Apollo 13' but got '%v'`, title)
+	}
+}
+
+func TestInputWithBatchParams(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Query":"UNWIND $batch AS row MATCH (m:Movie {title:row.Title}) RETURN m.title AS title","Database":"neo4j","ParamMode":"Batch","ParamBatchSize":1,"Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}]}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jInputParams.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Movie`]); rows != 2 {
+		t.Fatalf(`expected 2 rows but got %v`, rows)
+	}
+	t.Logf(`%v`, collector.Data)
+}
+
+func TestInputRowLimitWithAutoCount(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","Query":"MATCH (m:Movie) RETURN m.title AS title","Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}],"AutoCount":true,"RowLimit":5}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+	if rows := len(collector.Data[`Movie`]); rows != 5 {
+		t.Fatalf(`expected 5 rows but got %v`, rows)
+	}
+}
+
+func TestInputSkipPaging(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","Query":"MATCH (m:Movie) RETURN m.title AS title ORDER BY title","Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}],"PageMode":"Skip","PageSize":7}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	expected, err := checkNumberOfItems(`MATCH (m:Movie) RETURN count(m)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if rows := len(collector.Data[`Movie`]); rows != expected {
+		t.Fatalf(`expected %v rows but got %v`, expected, rows)
+	}
+}
+
+func TestInputSkipPagingWithoutOrderByDoesNotRun(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","Query":"MATCH (m:Movie) RETURN m.title AS title","Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}],"PageMode":"Skip","PageSize":7}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Movie`]); rows != 0 {
+		t.Fatalf(`expected 0 rows but got %v`, rows)
+	}
+}
+
+func TestInputKeysetPaging(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","Query":"MATCH (m:Movie) WHERE $lastKey IS NULL OR m.title &gt; $lastKey RETURN m.title AS title ORDER BY title LIMIT $pageSize","Fields":[{"Name":"Movie","DataType":"String","Path":[{"Key":"title","DataType":"String"}]}],"PageMode":"Keyset","PageSize":7,"PageKey":"title"}</JSON>
+</Configuration>`
+	plugin := &input.Neo4jInput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	collector := runner.CaptureOutgoingAnchor(`Output`)
+	runner.SimulateLifecycle()
+
+	expected, err := checkNumberOfItems(`MATCH (m:Movie) RETURN count(m)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if rows := len(collector.Data[`Movie`]); rows != expected {
+		t.Fatalf(`expected %v rows but got %v`, expected, rows)
+	}
+}
+
 func TestAdHocQuery(t *testing.T) {
 	conn, err := openSession()
 	if err != nil {
@@ -84,12 +201,12 @@ func TestAdHocQuery(t *testing.T) {
 	defer conn.Close()
 
 	query := `MATCH p = (:Person)-[*0..2]-(:Person) RETURN p SKIP 119 LIMIT 1`
-	_, err = conn.session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		result, txErr := tx.Run(query, nil)
+	_, err = conn.session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(ctx, query, nil)
 		if txErr != nil {
 			return nil, txErr
 		}
-		for result.Next() {
+		for result.Next(ctx) {
 			record := result.Record()
 			if p, ok := record.Get(`p`); ok {
 				path := p.(neo4j.Path)
@@ -104,7 +221,7 @@ func TestAdHocQuery(t *testing.T) {
 			return nil, txErr
 		}
 
-		return result.Consume()
+		return result.Consume(ctx)
 	})
 }
 
@@ -120,12 +237,12 @@ func TestConnection(t *testing.T) {
 	//query := `CALL db.relationshipTypes()`
 	query := `MATCH p=()-[r:ACTED_IN]->() RETURN p`
 
-	_, err = conn.session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		result, txErr := tx.Run(query, nil)
+	_, err = conn.session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(ctx, query, nil)
 		if txErr != nil {
 			return nil, txErr
 		}
-		for result.Next() {
+		for result.Next(ctx) {
 			record := result.Record()
 			if p, ok := record.Get(`p`); ok {
 				path := p.(neo4j.Path)
@@ -137,7 +254,7 @@ func TestConnection(t *testing.T) {
 			return nil, txErr
 		}
 
-		return result.Consume()
+		return result.Consume(ctx)
 	})
 
 }
@@ -149,8 +266,8 @@ func TestOutput(t *testing.T) {
 	}
 	defer conn.Close()
 
-	_, err = conn.session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		_, _ = tx.Run(``, nil)
+	_, err = conn.session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, _ = tx.Run(ctx, ``, nil)
 		return nil, nil
 	})
 }
@@ -177,6 +294,313 @@ func TestBatch(t *testing.T) {
 	}
 }
 
+func TestOutputSummary(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":2,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[]}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Summary`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Summary Type`]); rows != 3 {
+		t.Fatalf(`expected 3 summary rows but got %v`, rows)
+	}
+	if summaryType := collector.Data[`Summary Type`][2]; summaryType != `Total` {
+		t.Fatalf(`expected 'Total' but got '%v'`, summaryType)
+	}
+	if created := collector.Data[`Nodes Created`][2]; created != 3 {
+		t.Fatalf(`expected 3 nodes created but got %v`, created)
+	}
+	t.Logf(`%v`, collector.Data)
+}
+
+func TestOutputDynamicLabels(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":[],"NodeExtraLabels":["Extra"],"NodeLabelField":"Labels","RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[]}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputLabels.txt`)
+	runner.SimulateLifecycle()
+
+	extra, err := checkNumberOfItems(`MATCH (n:TestLabel:Extra) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if extra != 3 {
+		t.Fatalf(`expected 3 Extra nodes but got %v`, extra)
+	}
+	people, err := checkNumberOfItems(`MATCH (n:TestLabel:Person) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if people != 2 {
+		t.Fatalf(`expected 2 Person nodes but got %v`, people)
+	}
+	employees, err := checkNumberOfItems(`MATCH (n:TestLabel:Employee) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if employees != 1 {
+		t.Fatalf(`expected 1 Employee node but got %v`, employees)
+	}
+}
+
+func TestOutputRenamedProperties(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":[{"ID":"Key"}],"NodePropFields":[{"Value":"Text"}],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[]}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) WHERE n.Key IS NOT NULL AND n.Text IS NOT NULL RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 3 {
+		t.Fatalf(`expected 3 records but got %v`, records)
+	}
+}
+
+func TestOutputDurationFields(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Window"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"DurationFields":["Window"]}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputDurations.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) WHERE valueType(n.Window) STARTS WITH 'DURATION' RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 3 {
+		t.Fatalf(`expected 3 records but got %v`, records)
+	}
+}
+
+func TestOutputDelimitedListFields(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Tags"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"ListFields":[{"Field":"Tags","Delimiter":"|","Type":"String"}]}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputLists.txt`)
+	runner.SimulateLifecycle()
+
+	items, err := checkNumberOfItems(`MATCH (n:TestLabel) UNWIND n.Tags AS tag RETURN count(tag)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if items != 4 {
+		t.Fatalf(`expected 4 list items but got %v`, items)
+	}
+}
+
+func TestOutputParallelWriters(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":1,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"Writers":2,"PartitionByKey":true}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Summary`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 3 {
+		t.Fatalf(`expected 3 records but got %v`, records)
+	}
+	if rows := len(collector.Data[`Summary Type`]); rows != 4 {
+		t.Fatalf(`expected 4 summary rows but got %v`, rows)
+	}
+}
+
+func TestOutputParallelMergeRequiresConstraint(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":1,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"Writers":2}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 0 {
+		t.Fatalf(`expected 0 records but got %v`, records)
+	}
+}
+
+func TestOutputResumesFromCheckpoint(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	checkpointFile := filepath.Join(t.TempDir(), `checkpoint.json`)
+	jsonConfig := fmt.Sprintf(`{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":2,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"Checkpoint":"File","CheckpointFile":%q}`, checkpointFile)
+	var decoded output.Configuration
+	err = json.Unmarshal([]byte(jsonConfig), &decoded)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	hash, err := output.ConfigHash(decoded)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	store := &output.FileCheckpointStore{Path: checkpointFile}
+	err = store.Save(hash, output.CheckpointState{Records: 1, Ranges: []output.RecordRange{{Start: 2, End: 3}}})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>` + jsonConfig + `</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 1 {
+		t.Fatalf(`expected 1 record after skipping the written records but got %v`, records)
+	}
+	if _, err = os.Stat(checkpointFile); !os.IsNotExist(err) {
+		t.Fatalf(`expected the checkpoint file to be removed after a complete load but got %v`, err)
+	}
+}
+
+func TestOutputNodeCheckpointIsWrittenWithBatch(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":1,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"Checkpoint":"Node"}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputErrors.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 1 {
+		t.Fatalf(`expected 1 record before the failed batch but got %v`, records)
+	}
+	ranges, err := checkNumberOfItems(`MATCH (c:GraphyxCheckpointRange) WHERE c.starts = [0] AND c.ends = [1] RETURN count(c)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if ranges != 1 {
+		t.Fatalf(`expected the written batch to be in the checkpoint but got %v ranges`, ranges)
+	}
+
+	err = deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestOutputDryRunDoesNotWrite(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":2,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"DryRun":true}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 0 {
+		t.Fatalf(`expected 0 records but got %v`, records)
+	}
+}
+
+func TestOutputDryRunExplainsLabelsFromLaterBatches(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":1,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":[],"NodeLabelField":"Labels","RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"DryRun":true}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputLabels.txt`)
+	runner.SimulateLifecycle()
+
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 0 {
+		t.Fatalf(`expected 0 records but got %v`, records)
+	}
+}
+
 func TestDataTypesCopyCorrectly(t *testing.T) {
 	err := deleteTestStuff()
 	if err != nil {
@@ -206,6 +630,39 @@ func TestDataTypesCopyCorrectly(t *testing.T) {
 	}
 }
 
+func TestOutputFailedRowsGoToErrors(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[],"BisectErrors":true}</JSON>
+</Configuration>`
+	plugin := &output.Neo4jOutput{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jOutputErrors.txt`)
+	collector := runner.CaptureOutgoingAnchor(`Errors`)
+	runner.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Value`]); rows != 1 {
+		t.Fatalf(`expected 1 error row but got %v`, rows)
+	}
+	if value := collector.Data[`Value`][0]; value != `Null ID` {
+		t.Fatalf(`expected 'Null ID' but got '%v'`, value)
+	}
+	if code := collector.Data[`Neo4j Error Code`][0]; code == `` {
+		t.Fatalf(`expected an error code but got none`)
+	}
+	records, err := checkNumberOfItems(`MATCH (n:TestLabel) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if records != 3 {
+		t.Fatalf(`expected 3 records but got %v`, records)
+	}
+}
+
 func TestDoNotRunOutputIfUpdateOnly(t *testing.T) {
 	err := deleteTestStuff()
 	if err != nil {
@@ -264,6 +721,45 @@ func TestEndToEnd(t *testing.T) {
 	}
 }
 
+func TestReportUnmatchedEndpoints(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	configNodes := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Node","BatchSize":10000,"NodeLabel":"TestLabel","NodeIdFields":["ID"],"NodePropFields":["Value"],"RelLabel":"","RelPropFields":[],"RelLeftLabel":"","RelLeftFields":[],"RelRightLabel":"","RelRightFields":[]}</JSON>
+</Configuration>`
+	pluginNodes := &output.Neo4jOutput{}
+	runnerNodes := sdk.RegisterToolTest(pluginNodes, 1, configNodes)
+	runnerNodes.ConnectInput(`Input`, `TestNeo4jOutputNodes.txt`)
+	runnerNodes.SimulateLifecycle()
+
+	configRelationships := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","ExportObject":"Relationship","BatchSize":10000,"NodeLabel":"","NodeIdFields":[],"NodePropFields":[],"RelLabel":"TestRel","RelPropFields":["Value"],"RelLeftLabel":"TestLabel","RelLeftFields":[{"LeftID":"ID"}],"RelRightLabel":"TestLabel","RelRightFields":[{"RightID":"ID"}],"RelEndpointMode":"Report"}</JSON>
+</Configuration>`
+	pluginRelationships := &output.Neo4jOutput{}
+	runnerRelationships := sdk.RegisterToolTest(pluginRelationships, 100, configRelationships)
+	runnerRelationships.ConnectInput(`Input`, `TestNeo4jOutputUnmatched.txt`)
+	collector := runnerRelationships.CaptureOutgoingAnchor(`Unmatched`)
+	runnerRelationships.SimulateLifecycle()
+
+	if rows := len(collector.Data[`Missing Endpoint`]); rows != 3 {
+		t.Fatalf(`expected 3 unmatched rows but got %v`, rows)
+	}
+	for index, missing := range collector.Data[`Missing Endpoint`] {
+		value := collector.Data[`Value`][index]
+		if value != missing {
+			t.Fatalf(`expected row '%v' to be missing '%v' but got '%v'`, value, value, missing)
+		}
+	}
+
+	err = deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
 func checkNumberOfItems(query string) (int, error) {
 	conn, err := openSession()
 	if err != nil {
@@ -271,15 +767,15 @@ func checkNumberOfItems(query string) (int, error) {
 	}
 	defer conn.Close()
 
-	result, err := conn.session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		result, txErr := tx.Run(query, nil)
+	result, err := conn.session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(ctx, query, nil)
 		if txErr != nil {
 			return nil, txErr
 		}
 		if txErr = result.Err(); txErr != nil {
 			return nil, txErr
 		}
-		hasRecord := result.Next()
+		hasRecord := result.Next(ctx)
 		if !hasRecord {
 			return nil, fmt.Errorf(`no record was returned`)
 		}
@@ -322,6 +818,74 @@ func TestEndToEndDeleteNodes(t *testing.T) {
 	}
 }
 
+func TestDeleteDryRunDoesNotDelete(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = addStuffForDeletion()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	before, err := checkNumberOfItems(`MATCH (n:DELETE) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"neo4j","DeleteObject":"Node","BatchSize":10000,"NodeLabel":"DELETE","NodeIdFields":["Id"],"DryRun":true}</JSON>
+</Configuration>`
+	plugin := &delete.Neo4jDelete{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jDeleteNodes.txt`)
+	runner.SimulateLifecycle()
+
+	after, err := checkNumberOfItems(`MATCH (n:DELETE) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if after != before {
+		t.Fatalf(`expected %v nodes but got %v`, before, after)
+	}
+
+	err = deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
+func TestDeleteStopsAfterFailedBatch(t *testing.T) {
+	err := deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = addStuffForDeletion()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"test","Password":"test","Database":"graphyxmissingdatabase","DeleteObject":"Node","BatchSize":1,"NodeLabel":"DELETE","NodeIdFields":["Id"]}</JSON>
+</Configuration>`
+	plugin := &delete.Neo4jDelete{}
+	runner := sdk.RegisterToolTest(plugin, 1, config)
+	runner.ConnectInput(`Input`, `TestNeo4jDeleteNodes.txt`)
+	runner.SimulateLifecycle()
+
+	nodes, err := checkNumberOfItems(`MATCH (n:DELETE) RETURN count(n)`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if nodes != 3 {
+		t.Fatalf(`expected 3 but got %v`, nodes)
+	}
+
+	err = deleteTestStuff()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+}
+
 func TestEndToEndDeleteRelationships(t *testing.T) {
 	err := deleteTestStuff()
 	if err != nil {
@@ -395,20 +959,20 @@ CREATE (n1)-[:Relates_To]->(n2);
 MATCH (n2:DELETE), (n3:DELETE) WHERE n2.Id=2 AND n3.Id=3
 CREATE (n2)-[:Relates_To]->(n3);`
 
-	_, err = conn.session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+	_, err = conn.session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 		queries := strings.Split(createQuery, `;`)
 		for _, query := range queries {
 			if query == `` {
 				continue
 			}
-			result, txErr := tx.Run(query, nil)
+			result, txErr := tx.Run(ctx, query, nil)
 			if txErr != nil {
 				return nil, txErr
 			}
 			if txErr = result.Err(); txErr != nil {
 				return nil, txErr
 			}
-			_, txErr = result.Consume()
+			_, txErr = result.Consume(ctx)
 			if txErr != nil {
 				return nil, txErr
 			}
@@ -427,22 +991,23 @@ func deleteTestStuff() error {
 	defer conn.Close()
 
 	deleteNodes := `MATCH (n:TestLabel) DETACH DELETE n;
-MATCH (n:DELETE) DETACH DELETE n;`
+MATCH (n:DELETE) DETACH DELETE n;
+MATCH (n) WHERE n:GraphyxCheckpoint OR n:GraphyxCheckpointRange DELETE n;`
 
-	_, err = conn.session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+	_, err = conn.session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 		queries := strings.Split(deleteNodes, `;`)
 		for _, query := range queries {
 			if query == `` {
 				continue
 			}
-			result, txErr := tx.Run(query, nil)
+			result, txErr := tx.Run(ctx, query, nil)
 			if txErr != nil {
 				return nil, txErr
 			}
 			if txErr = result.Err(); txErr != nil {
 				return nil, txErr
 			}
-			_, txErr = result.Consume()
+			_, txErr = result.Consume(ctx)
 			if txErr != nil {
 				return nil, txErr
 			}
@@ -453,14 +1018,16 @@ MATCH (n:DELETE) DETACH DELETE n;`
 	return err
 }
 
+var ctx = context.Background()
+
 type ConnStuff struct {
-	driver  neo4j.Driver
-	session neo4j.Session
+	driver  neo4j.DriverWithContext
+	session neo4j.SessionWithContext
 }
 
 func (c *ConnStuff) Close() {
-	_ = c.session.Close()
-	_ = c.driver.Close()
+	_ = c.session.Close(ctx)
+	_ = c.driver.Close(ctx)
 }
 
 func openSession() (*ConnStuff, error) {
@@ -468,12 +1035,12 @@ func openSession() (*ConnStuff, error) {
 	database := `neo4j`
 	username := `test`
 	password := `test`
-	driver, err := neo4j.NewDriver(uri, neo4j.BasicAuth(username, password, ""))
+	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
 	if err != nil {
 		return nil, err
 	}
 
-	session := driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
 	return &ConnStuff{
 		driver:  driver,
 		session: session,
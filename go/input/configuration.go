@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"math"
 	"strconv"
@@ -17,18 +17,42 @@ type XmlJson struct {
 }
 
 type Configuration struct {
-	ConnStr  string
-	Username string
-	Password string
-	Query    string
-	Database string
-	Fields   []Field
+	ConnStr                             string
+	Username                            string
+	Password                            string
+	Query                               string
+	Database                            string
+	Fields                              []Field
+	ParamMode                           string
+	ParamFields                         []string
+	ParamBatchSize                      int
+	CredentialProvider                  string
+	CredentialFile                      string
+	CredentialPrecedence                string
+	AuthType                            string
+	Realm                               string
+	TlsCaFile                           string
+	TlsSkipVerify                       bool
+	MaxConnectionPoolSize               int
+	MaxTransactionRetrySeconds          int
+	ConnectionAcquisitionTimeoutSeconds int
+	UserAgent                           string
+	CountQuery                          string
+	AutoCount                           bool
+	RowLimit                            int
+	PageMode                            string
+	PageSize                            int
+	PageKey                             string
+	ZoneColumns                         string
 }
 
 type Field struct {
-	Name     string
-	DataType string
-	Path     []Element
+	Name          string
+	DataType      string
+	Path          []Element
+	Size          int
+	Scale         int
+	ScaledInteger bool
 }
 
 type Element struct {
@@ -72,25 +96,45 @@ func (i *pathIterator) NextField() (Element, bool) {
 const source string = `Neo4j Input`
 
 func CreateOutgoingObjects(fields []Field) (OutgoingObjects, error) {
-	getValueFuncs := make([]GetValueFunc, len(fields))
-	transferFuncs := make([]TransferFunc, len(fields))
+	return CreateOutgoingObjectsWithZones(fields, ``)
+}
+
+func CreateOutgoingObjectsWithZones(fields []Field, zoneColumns string) (OutgoingObjects, error) {
+	zoneSuffix, err := zoneColumnSuffix(zoneColumns)
+	if err != nil {
+		return OutgoingObjects{}, err
+	}
+	var outgoingFields []Field
+	var getValueFuncs []GetValueFunc
 	editor := sdk.EditingRecordInfo{}
-	var err error
-	for index, field := range fields {
+	for _, field := range fields {
 		field.Name, err = addFieldToEditor(field, &editor)
 		if err != nil {
 			return OutgoingObjects{}, err
 		}
 		iterator := &pathIterator{elements: field.Path}
-		getValueFuncs[index], err = generateTransferFunc(iterator, field)
+		getValueFunc, err := generateTransferFunc(iterator, field)
 		if err != nil {
 			return OutgoingObjects{}, err
 		}
+		outgoingFields = append(outgoingFields, field)
+		getValueFuncs = append(getValueFuncs, getValueFunc)
+		if zoneSuffix == `` || field.DataType != `DateTime` {
+			continue
+		}
+		zoneField := Field{Name: field.Name + zoneSuffix, DataType: `String`}
+		zoneField.Name, err = addFieldToEditor(zoneField, &editor)
+		if err != nil {
+			return OutgoingObjects{}, err
+		}
+		outgoingFields = append(outgoingFields, zoneField)
+		getValueFuncs = append(getValueFuncs, zoneTransferFunc(getValueFunc, zoneColumns))
 	}
 	outInfo := editor.GenerateOutgoingRecordInfo()
+	transferFuncs := make([]TransferFunc, len(getValueFuncs))
 	for index, getValueFunc := range getValueFuncs {
-		fieldName := fields[index].Name
-		fieldType := fields[index].DataType
+		fieldName := outgoingFields[index].Name
+		fieldType := outgoingFields[index].DataType
 		switch fieldType {
 		case `Integer`:
 			transferFuncs[index] = integerTransferFunc(fieldName, outInfo, getValueFunc)
@@ -102,6 +146,8 @@ func CreateOutgoingObjects(fields []Field) (OutgoingObjects, error) {
 			transferFuncs[index] = stringTransferFunc(fieldName, outInfo, getValueFunc)
 		case `Date`, `DateTime`:
 			transferFuncs[index] = dateTimeTransferFunc(fieldName, outInfo, getValueFunc)
+		case `FixedDecimal`:
+			transferFuncs[index] = fixedDecimalTransferFunc(outgoingFields[index], outInfo, getValueFunc)
 		default:
 			return OutgoingObjects{}, fmt.Errorf(`invalid field type '%v' for field '%v'`, fieldType, fieldName)
 		}
@@ -123,6 +169,12 @@ func addFieldToEditor(field Field, editor *sdk.EditingRecordInfo) (string, error
 		return editor.AddDateTimeField(field.Name, source), nil
 	case `String`:
 		return editor.AddV_WStringField(field.Name, source, 1073741823), nil
+	case `FixedDecimal`:
+		size, scale := fixedDecimalSize(field)
+		if scale >= size {
+			return ``, fmt.Errorf(`field %v has a FixedDecimal scale of %v, which must be smaller than its size of %v`, field.Name, scale, size)
+		}
+		return editor.AddFixedDecimalField(field.Name, source, size, scale), nil
 	default:
 		return ``, fmt.Errorf(`field %v is invalid type %v`, field.Name, field.DataType)
 	}
@@ -293,6 +345,18 @@ func generateTransferFunc(iterator *pathIterator, field Field) (GetValueFunc, er
 			return relValue, nil
 		}
 		return relationshipTransferFunc(iterator, field, extractRelationshipFunc)
+	case `Point`:
+		extractPointFunc := func(record *neo4j.Record) (interface{}, error) {
+			value, _ := record.Get(element.Key)
+			return value, nil
+		}
+		return pointTransferFunc(iterator, field, extractPointFunc)
+	case `Duration`:
+		extractDurationFunc := func(record *neo4j.Record) (interface{}, error) {
+			value, _ := record.Get(element.Key)
+			return value, nil
+		}
+		return durationTransferFunc(iterator, field, extractDurationFunc)
 	case `Path`:
 		extractPathFunc := func(record *neo4j.Record) (neo4j.Path, error) {
 			value, exists := record.Get(element.Key)
@@ -330,6 +394,17 @@ func nodeTransferFunc(iterator *pathIterator, field Field, nodeExtractor extract
 			}
 			return node.Id, nil
 		}, nil
+	case `ElementId`:
+		return func(record *neo4j.Record) (interface{}, error) {
+			node, err := nodeExtractor(record)
+			if err != nil {
+				return nil, err
+			}
+			if node.Id == math.MinInt64 {
+				return nil, nil
+			}
+			return node.ElementId, nil
+		}, nil
 	case `Labels`:
 		nodeFunc := func(record *neo4j.Record) ([]string, error) {
 			node, err := nodeExtractor(record)
@@ -406,6 +481,39 @@ func relationshipTransferFunc(iterator *pathIterator, field Field, relExtractor
 			}
 			return relationship.EndId, nil
 		}, nil
+	case `ElementId`:
+		return func(record *neo4j.Record) (interface{}, error) {
+			relationship, err := relExtractor(record)
+			if err != nil {
+				return nil, err
+			}
+			if relationship.Id == math.MinInt64 {
+				return nil, nil
+			}
+			return relationship.ElementId, nil
+		}, nil
+	case `StartElementId`:
+		return func(record *neo4j.Record) (interface{}, error) {
+			relationship, err := relExtractor(record)
+			if err != nil {
+				return nil, err
+			}
+			if relationship.Id == math.MinInt64 {
+				return nil, nil
+			}
+			return relationship.StartElementId, nil
+		}, nil
+	case `EndElementId`:
+		return func(record *neo4j.Record) (interface{}, error) {
+			relationship, err := relExtractor(record)
+			if err != nil {
+				return nil, err
+			}
+			if relationship.Id == math.MinInt64 {
+				return nil, nil
+			}
+			return relationship.EndElementId, nil
+		}, nil
 	case `Type`:
 		return func(record *neo4j.Record) (interface{}, error) {
 			relationship, err := relExtractor(record)
@@ -794,6 +902,24 @@ func mapTransferFunc(iterator *pathIterator, field Field, extract extractMap) (G
 			return listValue, nil
 		}
 		return listTransferFunc(iterator, field, listFunc)
+	case `Point`:
+		pointFunc := func(record *neo4j.Record) (interface{}, error) {
+			extractedMap, err := extract(record)
+			if err != nil {
+				return nil, err
+			}
+			return extractedMap[element.Key], nil
+		}
+		return pointTransferFunc(iterator, field, pointFunc)
+	case `Duration`:
+		durationFunc := func(record *neo4j.Record) (interface{}, error) {
+			extractedMap, err := extract(record)
+			if err != nil {
+				return nil, err
+			}
+			return extractedMap[element.Key], nil
+		}
+		return durationTransferFunc(iterator, field, durationFunc)
 	default:
 		return nil, fmt.Errorf(`field %v has an invalid data type '%v' for Map`, field.Name, element.DataType)
 	}
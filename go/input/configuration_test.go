@@ -1,7 +1,8 @@
 package input_test
 
 import (
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"bytes"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/graphyx/input"
 	"reflect"
 	"testing"
@@ -53,6 +54,100 @@ func TestBasicConfig(t *testing.T) {
 	t.Logf(`%v`, decoded)
 }
 
+func TestParamConfig(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"user","Password":"password","Database":"neo4j","Query":"UNWIND $batch AS row MATCH (c:Customer {id:row.Id})--(n) RETURN n.name","Fields":[],"ParamMode":"Batch","ParamFields":["Id"],"ParamBatchSize":1000}</JSON>
+</Configuration>`
+
+	decoded, err := input.DecodeConfig(config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if decoded.ParamMode != `Batch` {
+		t.Fatalf(`expected 'Batch' but got '%v'`, decoded.ParamMode)
+	}
+	if !reflect.DeepEqual(decoded.ParamFields, []string{`Id`}) {
+		t.Fatalf(`expected [Id] but got %v`, decoded.ParamFields)
+	}
+	if decoded.ParamBatchSize != 1000 {
+		t.Fatalf(`expected 1000 but got %v`, decoded.ParamBatchSize)
+	}
+}
+
+func TestProgressConfig(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"user","Password":"password","Database":"neo4j","Query":"MATCH (n) RETURN n","Fields":[],"CountQuery":"MATCH (n) RETURN count(n)","AutoCount":true,"RowLimit":100}</JSON>
+</Configuration>`
+
+	decoded, err := input.DecodeConfig(config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if decoded.CountQuery != `MATCH (n) RETURN count(n)` {
+		t.Fatalf(`expected 'MATCH (n) RETURN count(n)' but got '%v'`, decoded.CountQuery)
+	}
+	if !decoded.AutoCount {
+		t.Fatalf(`expected AutoCount to be true`)
+	}
+	if decoded.RowLimit != 100 {
+		t.Fatalf(`expected 100 but got %v`, decoded.RowLimit)
+	}
+}
+
+func TestWrapCountQuery(t *testing.T) {
+	query := input.WrapCountQuery(`MATCH (n:Person) RETURN n`)
+	expected := "CALL {\nMATCH (n:Person) RETURN n\n} RETURN count(*)"
+	if query != expected {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestPagingConfig(t *testing.T) {
+	config := `<Configuration>
+  <JSON>{"ConnStr":"bolt://localhost:7687","Username":"user","Password":"password","Database":"neo4j","Query":"MATCH (n) RETURN n","Fields":[],"PageMode":"Keyset","PageSize":500,"PageKey":"id"}</JSON>
+</Configuration>`
+
+	decoded, err := input.DecodeConfig(config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if decoded.PageMode != `Keyset` {
+		t.Fatalf(`expected 'Keyset' but got '%v'`, decoded.PageMode)
+	}
+	if decoded.PageSize != 500 {
+		t.Fatalf(`expected 500 but got %v`, decoded.PageSize)
+	}
+	if decoded.PageKey != `id` {
+		t.Fatalf(`expected 'id' but got '%v'`, decoded.PageKey)
+	}
+}
+
+func TestSkipPagedQuery(t *testing.T) {
+	query, err := input.SkipPagedQuery("MATCH (n:Person) RETURN n.limit AS maximum ORDER BY n.name\n")
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := "MATCH (n:Person) RETURN n.limit AS maximum ORDER BY n.name\nSKIP $offset LIMIT $pageSize"
+	if query != expected {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestSkipPagedQueryRejectsQueriesItCannotPage(t *testing.T) {
+	queries := []string{
+		`MATCH (n:Person) RETURN n ORDER BY n.name;`,
+		`MATCH (n:Person) RETURN n ORDER BY n.name LIMIT 10`,
+		"MATCH (n:Person) RETURN n ORDER BY n.name\nskip 5",
+		`MATCH (n:Person) RETURN n`,
+	}
+	for _, query := range queries {
+		_, err := input.SkipPagedQuery(query)
+		if err == nil {
+			t.Fatalf("expected an error for\n\n%v\n\nbut got none", query)
+		}
+	}
+}
+
 func TestOutgoingRecordInfoFromConfig(t *testing.T) {
 	fields := []input.Field{
 		{
@@ -347,6 +442,40 @@ func TestNodeIdRecordInfo(t *testing.T) {
 	}
 }
 
+func TestNodeElementIdToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Node`},
+				{Key: `ElementId`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Node{ElementId: `4:abc:23`},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if count := len(outgoingStuff.TransferFuncs); count != 1 {
+		t.Fatalf(`expected 1 transfer func but got %v`, count)
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, isNull := outgoingStuff.RecordInfo.StringFields[`Field1`].GetCurrentString()
+	if isNull {
+		t.Fatalf(`expected non-null but got null`)
+	}
+	if value != `4:abc:23` {
+		t.Fatalf(`expected '4:abc:23' but got '%v'`, value)
+	}
+}
+
 func TestNodeStringRecordInfo(t *testing.T) {
 	fields := []input.Field{
 		{
@@ -937,6 +1066,108 @@ func TestRelationshipEndIdToRecordInfo(t *testing.T) {
 	}
 }
 
+func TestRelationshipElementIdToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Relationship`},
+				{Key: `ElementId`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Relationship{ElementId: `5:abc:452`},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if count := len(outgoingStuff.TransferFuncs); count != 1 {
+		t.Fatalf(`expected 1 transfer func but got %v`, count)
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, isNull := outgoingStuff.RecordInfo.StringFields[`Field1`].GetCurrentString()
+	if isNull {
+		t.Fatalf(`expected non-null but got null`)
+	}
+	if value != `5:abc:452` {
+		t.Fatalf(`expected '5:abc:452' but got '%v'`, value)
+	}
+}
+
+func TestRelationshipStartElementIdToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Relationship`},
+				{Key: `StartElementId`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Relationship{StartElementId: `4:abc:1`},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if count := len(outgoingStuff.TransferFuncs); count != 1 {
+		t.Fatalf(`expected 1 transfer func but got %v`, count)
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, isNull := outgoingStuff.RecordInfo.StringFields[`Field1`].GetCurrentString()
+	if isNull {
+		t.Fatalf(`expected non-null but got null`)
+	}
+	if value != `4:abc:1` {
+		t.Fatalf(`expected '4:abc:1' but got '%v'`, value)
+	}
+}
+
+func TestRelationshipEndElementIdToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Relationship`},
+				{Key: `EndElementId`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Relationship{EndElementId: `4:abc:2`},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if count := len(outgoingStuff.TransferFuncs); count != 1 {
+		t.Fatalf(`expected 1 transfer func but got %v`, count)
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, isNull := outgoingStuff.RecordInfo.StringFields[`Field1`].GetCurrentString()
+	if isNull {
+		t.Fatalf(`expected non-null but got null`)
+	}
+	if value != `4:abc:2` {
+		t.Fatalf(`expected '4:abc:2' but got '%v'`, value)
+	}
+}
+
 func TestRelationshipTypeToRecordInfo(t *testing.T) {
 	fields := []input.Field{
 		{
@@ -2159,3 +2390,480 @@ func TestNodeDateTimeListPropertyToRecordInfo(t *testing.T) {
 		t.Fatalf(`expected 2020-01-02 but got %v`, value)
 	}
 }
+
+func TestPointToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `X`,
+			DataType: `Float`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `X`, DataType: `Float`},
+			},
+		},
+		{
+			Name:     `Z`,
+			DataType: `Float`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `Z`, DataType: `Float`},
+			},
+		},
+		{
+			Name:     `SRID`,
+			DataType: `Integer`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `SRID`, DataType: `Integer`},
+			},
+		},
+		{
+			Name:     `WKT`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `ToWKT`, DataType: `String`},
+			},
+		},
+		{
+			Name:     `GeoJSON`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `ToGeoJSON`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Point2D{X: -122.5, Y: 45.25, SpatialRefId: 4326},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, transfer := range outgoingStuff.TransferFuncs {
+		err = transfer(record)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	x, _ := outgoingStuff.RecordInfo.FloatFields[`X`].GetCurrentFloat()
+	if x != -122.5 {
+		t.Fatalf(`expected -122.5 but got %v`, x)
+	}
+	if isNull := outgoingStuff.RecordInfo.FloatFields[`Z`].GetNull(); !isNull {
+		t.Fatalf(`expected null Z for a 2D point but got non-null`)
+	}
+	srid, _ := outgoingStuff.RecordInfo.IntFields[`SRID`].GetCurrentInt()
+	if srid != 4326 {
+		t.Fatalf(`expected 4326 but got %v`, srid)
+	}
+	wkt, _ := outgoingStuff.RecordInfo.StringFields[`WKT`].GetCurrentString()
+	if expected := `POINT (-122.5 45.25)`; wkt != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, wkt)
+	}
+	geoJson, _ := outgoingStuff.RecordInfo.StringFields[`GeoJSON`].GetCurrentString()
+	if expected := `{"coordinates":[-122.5,45.25],"type":"Point"}`; geoJson != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, geoJson)
+	}
+}
+
+func TestNodePoint3DPropertyToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Node`},
+				{Key: `Properties`, DataType: `Map`},
+				{Key: `Location`, DataType: `Point`},
+				{Key: `ToWKT`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Node{Props: map[string]interface{}{
+			`Location`: neo4j.Point3D{X: 1, Y: 2.5, Z: 3, SpatialRefId: 9157},
+		}},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, _ := outgoingStuff.RecordInfo.StringFields[`Field1`].GetCurrentString()
+	if expected := `POINT Z (1 2.5 3)`; value != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, value)
+	}
+}
+
+func TestInvalidPointKey(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `Float`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Point`},
+				{Key: `Latitude`, DataType: `Float`},
+			},
+		},
+	}
+	_, err := input.CreateOutgoingObjects(fields)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestDurationToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Days`,
+			DataType: `Integer`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Duration`},
+				{Key: `Days`, DataType: `Integer`},
+			},
+		},
+		{
+			Name:     `TotalSeconds`,
+			DataType: `Float`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Duration`},
+				{Key: `TotalSeconds`, DataType: `Float`},
+			},
+		},
+		{
+			Name:     `ISO`,
+			DataType: `String`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Duration`},
+				{Key: `ToString`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Duration{Days: 2, Seconds: 30, Nanos: 500000000},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, transfer := range outgoingStuff.TransferFuncs {
+		err = transfer(record)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	days, _ := outgoingStuff.RecordInfo.IntFields[`Days`].GetCurrentInt()
+	if days != 2 {
+		t.Fatalf(`expected 2 but got %v`, days)
+	}
+	total, _ := outgoingStuff.RecordInfo.FloatFields[`TotalSeconds`].GetCurrentFloat()
+	if total != 172830.5 {
+		t.Fatalf(`expected 172830.5 but got %v`, total)
+	}
+	iso, _ := outgoingStuff.RecordInfo.StringFields[`ISO`].GetCurrentString()
+	if expected := `P0M2DT30.500000000S`; iso != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, iso)
+	}
+}
+
+func TestNodeDurationPropertyToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `Integer`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `Node`},
+				{Key: `Properties`, DataType: `Map`},
+				{Key: `Window`, DataType: `Duration`},
+				{Key: `Months`, DataType: `Integer`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		neo4j.Node{Props: map[string]interface{}{
+			`Window`: neo4j.Duration{Months: 3},
+		}},
+	})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, _ := outgoingStuff.RecordInfo.IntFields[`Field1`].GetCurrentInt()
+	if value != 3 {
+		t.Fatalf(`expected 3 but got %v`, value)
+	}
+}
+
+func TestDateTimeZoneColumns(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `DateTime`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `DateTime`},
+			},
+		},
+	}
+	chicago, err := time.LoadLocation(`America/Chicago`)
+	if err != nil {
+		t.Skipf(`time zone database is not available: %v`, err.Error())
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		time.Date(2022, 3, 4, 5, 6, 7, 0, chicago),
+	})
+
+	expectedZones := map[string]string{`Offset`: `-06:00`, `Name`: `America/Chicago`}
+	expectedNames := map[string]string{`Offset`: `Field1_Offset`, `Name`: `Field1_Zone`}
+	for zoneColumns, expected := range expectedZones {
+		outgoingStuff, err := input.CreateOutgoingObjectsWithZones(fields, zoneColumns)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if count := len(outgoingStuff.TransferFuncs); count != 2 {
+			t.Fatalf(`expected 2 transfer funcs but got %v`, count)
+		}
+		for _, transfer := range outgoingStuff.TransferFuncs {
+			err = transfer(record)
+			if err != nil {
+				t.Fatalf(`expected no error but got: %v`, err.Error())
+			}
+		}
+		value, isNull := outgoingStuff.RecordInfo.StringFields[expectedNames[zoneColumns]].GetCurrentString()
+		if isNull || value != expected {
+			t.Fatalf(`expected '%v' but got '%v'`, expected, value)
+		}
+	}
+
+	_, err = input.CreateOutgoingObjectsWithZones(fields, `Abbreviation`)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestDateTimeZoneColumnForOffsetOnlyValue(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `DateTime`,
+			Path: []input.Element{
+				{Key: `value`, DataType: `DateTime`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{
+		time.Date(2022, 3, 4, 5, 6, 7, 0, time.FixedZone(`Offset`, 5*3600+1800)),
+	})
+	outgoingStuff, err := input.CreateOutgoingObjectsWithZones(fields, `Name`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = outgoingStuff.TransferFuncs[1](record)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, _ := outgoingStuff.RecordInfo.StringFields[`Field1_Zone`].GetCurrentString()
+	if value != `+05:30` {
+		t.Fatalf(`expected '+05:30' but got '%v'`, value)
+	}
+}
+
+func TestFixedDecimalToRecordInfo(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `FromString`,
+			DataType: `FixedDecimal`,
+			Size:     12,
+			Scale:    2,
+			Path: []input.Element{
+				{Key: `text`, DataType: `String`},
+			},
+		},
+		{
+			Name:          `FromCents`,
+			DataType:      `FixedDecimal`,
+			Size:          12,
+			Scale:         2,
+			ScaledInteger: true,
+			Path: []input.Element{
+				{Key: `cents`, DataType: `Integer`},
+			},
+		},
+		{
+			Name:     `FromInteger`,
+			DataType: `FixedDecimal`,
+			Path: []input.Element{
+				{Key: `cents`, DataType: `Integer`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`text`, `cents`}, []interface{}{`1234.56`, int64(-5)})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, transfer := range outgoingStuff.TransferFuncs {
+		err = transfer(record)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	expected := map[string]float64{`FromString`: 1234.56, `FromCents`: -0.05, `FromInteger`: -5}
+	for name, expectedValue := range expected {
+		value, isNull := outgoingStuff.RecordInfo.FloatFields[name].GetCurrentFloat()
+		if isNull || value != expectedValue {
+			t.Fatalf(`expected %v for %v but got %v`, expectedValue, name, value)
+		}
+	}
+}
+
+func TestFixedDecimalIsWrittenExactly(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `FromString`,
+			DataType: `FixedDecimal`,
+			Size:     25,
+			Scale:    6,
+			Path: []input.Element{
+				{Key: `text`, DataType: `String`},
+			},
+		},
+		{
+			Name:          `FromCents`,
+			DataType:      `FixedDecimal`,
+			Size:          25,
+			Scale:         2,
+			ScaledInteger: true,
+			Path: []input.Element{
+				{Key: `cents`, DataType: `Integer`},
+			},
+		},
+		{
+			Name:     `Rounded`,
+			DataType: `FixedDecimal`,
+			Size:     10,
+			Scale:    2,
+			Path: []input.Element{
+				{Key: `rounded`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`text`, `cents`, `rounded`}, []interface{}{`123456789012345.1234567`, int64(9007199254740993), `-0.125`})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	for _, transfer := range outgoingStuff.TransferFuncs {
+		err = transfer(record)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	expected := map[string]string{`FromString`: `123456789012345.123457`, `FromCents`: `90071992547409.93`, `Rounded`: `-0.13`}
+	for name, expectedValue := range expected {
+		value := fixedDecimalText(outgoingStuff.RecordInfo.FloatFields[name])
+		if value != expectedValue {
+			t.Fatalf(`expected '%v' for %v but got '%v'`, expectedValue, name, value)
+		}
+	}
+}
+
+func TestFixedDecimalTooLargeForField(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `FixedDecimal`,
+			Size:     6,
+			Scale:    2,
+			Path: []input.Element{
+				{Key: `value`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{`12345.67`})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+type floatFieldWithoutText struct {
+	value  float64
+	isNull bool
+}
+
+func (f *floatFieldWithoutText) GetNull() bool { return f.isNull }
+func (f *floatFieldWithoutText) SetNull()      { f.isNull = true }
+func (f *floatFieldWithoutText) SetFloat(value float64) {
+	f.value = value
+	f.isNull = false
+}
+func (f *floatFieldWithoutText) GetCurrentFloat() (float64, bool) { return f.value, f.isNull }
+
+func TestFixedDecimalWithoutTextFieldIsAnError(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `FixedDecimal`,
+			Size:     25,
+			Scale:    6,
+			Path: []input.Element{
+				{Key: `value`, DataType: `String`},
+			},
+		},
+	}
+	record := NewMockRecord([]string{`value`}, []interface{}{`123456789012345.1234567`})
+	outgoingStuff, err := input.CreateOutgoingObjects(fields)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	field := &floatFieldWithoutText{}
+	outgoingStuff.RecordInfo.FloatFields[`Field1`] = field
+	err = outgoingStuff.TransferFuncs[0](record)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+	if field.value != 0 {
+		t.Fatalf(`expected the float not to be written but got %v`, field.value)
+	}
+}
+
+func fixedDecimalText(field interface{}) string {
+	value := reflect.ValueOf(field).Elem()
+	size := int(value.FieldByName(`Size`).Int())
+	text := value.FieldByName(`CurrentValue`).Bytes()[:size]
+	if end := bytes.IndexByte(text, 0); end >= 0 {
+		text = text[:end]
+	}
+	return string(text)
+}
+
+func TestInvalidFixedDecimalScale(t *testing.T) {
+	fields := []input.Field{
+		{
+			Name:     `Field1`,
+			DataType: `FixedDecimal`,
+			Size:     4,
+			Scale:    4,
+			Path: []input.Element{
+				{Key: `value`, DataType: `String`},
+			},
+		},
+	}
+	_, err := input.CreateOutgoingObjects(fields)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
@@ -0,0 +1,115 @@
+package input
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+const defaultDecimalSize = 19
+const defaultDecimalScale = 2
+
+func fixedDecimalSize(field Field) (int, int) {
+	if field.Size == 0 {
+		return defaultDecimalSize, defaultDecimalScale
+	}
+	return field.Size, field.Scale
+}
+
+func fixedDecimalTransferFunc(field Field, info *sdk.OutgoingRecordInfo, getValueFunc GetValueFunc) TransferFunc {
+	fieldName := field.Name
+	size, scale := fixedDecimalSize(field)
+	return func(record *neo4j.Record) error {
+		value, getErr := getValueFunc(record)
+		if getErr != nil {
+			return getErr
+		}
+		if value == nil {
+			info.FloatFields[fieldName].SetNull()
+			return nil
+		}
+		decimal := new(big.Rat)
+		switch typed := value.(type) {
+		case string:
+			if _, ok := decimal.SetString(strings.TrimSpace(typed)); !ok {
+				return fmt.Errorf(`value %v is not a decimal for field %v`, value, fieldName)
+			}
+		case int64:
+			decimal.SetInt64(typed)
+			if field.ScaledInteger {
+				decimal.Quo(decimal, new(big.Rat).SetInt(pow10(scale)))
+			}
+		case float64:
+			info.FloatFields[fieldName].SetFloat(typed)
+			return nil
+		default:
+			return fmt.Errorf(`value %v is not a decimal for field %v`, value, fieldName)
+		}
+		text := FormatDecimal(decimal, scale)
+		if len(text) > size {
+			return fmt.Errorf(`value %v does not fit in field %v, which holds %v characters`, text, fieldName, size)
+		}
+		approximate, _ := decimal.Float64()
+		return setFixedDecimalText(info.FloatFields[fieldName], text, approximate)
+	}
+}
+
+func FormatDecimal(decimal *big.Rat, scale int) string {
+	scaled := new(big.Rat).Mul(decimal, new(big.Rat).SetInt(pow10(scale)))
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if new(big.Int).Mul(remainder.Abs(remainder), big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		if scaled.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return unscaleInteger(quotient.String(), scale)
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// SetFloat is the only setter the SDK has for FixedDecimal fields and it formats the value from a float64.
+// The field stores its value as text in CurrentValue, so the exact text is written over the formatted float.
+// If the SDK's field no longer looks like that, the value is reported as an error instead of being rounded through the float.
+func setFixedDecimalText(field sdk.OutgoingFloatField, text string, approximate float64) error {
+	value := reflect.ValueOf(field)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf(`cannot write the exact decimal %v because the SDK's FixedDecimal field is a %T`, text, field)
+	}
+	current := value.Elem().FieldByName(`CurrentValue`)
+	if current.Kind() != reflect.Slice || current.Type().Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf(`cannot write the exact decimal %v because the SDK's FixedDecimal field has no CurrentValue bytes`, text)
+	}
+	field.SetFloat(approximate)
+	bytes := current.Bytes()
+	size := len(bytes) - 1
+	if len(text) > size {
+		return fmt.Errorf(`cannot write the exact decimal %v because the SDK's FixedDecimal field holds %v characters`, text, size)
+	}
+	copy(bytes[:size], text)
+	if len(text) < size {
+		bytes[len(text)] = 0
+	}
+	return nil
+}
+
+func unscaleInteger(digits string, scale int) string {
+	if scale <= 0 {
+		return digits
+	}
+	sign := ``
+	if strings.HasPrefix(digits, `-`) {
+		sign, digits = `-`, digits[1:]
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat(`0`, scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	return sign + digits[:point] + `.` + digits[point:]
+}
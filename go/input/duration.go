@@ -0,0 +1,58 @@
+package input
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const secondsPerDay = 86400
+const secondsPerMonth = 2629746
+
+type extractDuration func(record *neo4j.Record) (interface{}, error)
+
+func durationTransferFunc(iterator *pathIterator, field Field, extract extractDuration) (GetValueFunc, error) {
+	element, ok := iterator.NextField()
+	if !ok {
+		return nil, fmt.Errorf(`the path for field %v ends in a Duration and not in a property data type`, field.Name)
+	}
+
+	var getValue func(neo4j.Duration) interface{}
+	switch element.Key {
+	case `Months`:
+		getValue = func(d neo4j.Duration) interface{} { return d.Months }
+	case `Days`:
+		getValue = func(d neo4j.Duration) interface{} { return d.Days }
+	case `Seconds`:
+		getValue = func(d neo4j.Duration) interface{} { return d.Seconds }
+	case `Nanos`:
+		getValue = func(d neo4j.Duration) interface{} { return int64(d.Nanos) }
+	case `TotalSeconds`:
+		getValue = func(d neo4j.Duration) interface{} { return DurationTotalSeconds(d) }
+	case `ToString`:
+		getValue = func(d neo4j.Duration) interface{} { return d.String() }
+	default:
+		return nil, fmt.Errorf(`field %v has an invalid key '%v' for Duration`, field.Name, element.Key)
+	}
+
+	return func(record *neo4j.Record) (interface{}, error) {
+		value, err := extract(record)
+		if err != nil {
+			return nil, err
+		}
+		switch typed := value.(type) {
+		case nil:
+			return nil, nil
+		case neo4j.Duration:
+			return getValue(typed), nil
+		case *neo4j.Duration:
+			return getValue(*typed), nil
+		default:
+			return nil, fmt.Errorf(`value %v is not a Duration for field %v, but is %T`, value, field.Name, value)
+		}
+	}, nil
+}
+
+func DurationTotalSeconds(d neo4j.Duration) float64 {
+	seconds := d.Months*secondsPerMonth + d.Days*secondsPerDay + d.Seconds
+	return float64(seconds) + float64(d.Nanos)/1e9
+}
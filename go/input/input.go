@@ -1,97 +1,293 @@
 package input
 
 import (
+	"context"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"github.com/tlarsendataguy/graphyx/util"
 )
 
 type Neo4jInput struct {
-	provider   sdk.Provider
-	output     sdk.OutputAnchor
-	outObjects OutgoingObjects
-	config     Configuration
+	provider         sdk.Provider
+	output           sdk.OutputAnchor
+	outObjects       OutgoingObjects
+	config           Configuration
+	ctx              context.Context
+	driver           neo4j.DriverWithContext
+	session          neo4j.SessionWithContext
+	configured       bool
+	hasInput         bool
+	doQuery          bool
+	copiers          []util.CopyData
+	params           map[string]interface{}
+	batch            []map[string]interface{}
+	currentBatchSize int
+	totalRows        int64
+	rowsRead         int64
+	lastPercent      int
 }
 
 func (i *Neo4jInput) Init(provider sdk.Provider) {
 	var err error
 	i.provider = provider
+	i.ctx = context.Background()
 	i.output = provider.GetOutputAnchor(`Output`)
 	i.config, err = DecodeConfig(provider.ToolConfig())
 	if err != nil {
 		i.provider.Io().Error(err.Error())
 		return
 	}
-	i.outObjects, err = CreateOutgoingObjects(i.config.Fields)
+	i.outObjects, err = CreateOutgoingObjectsWithZones(i.config.Fields, i.config.ZoneColumns)
 	if err != nil {
 		i.provider.Io().Error(err.Error())
+		return
+	}
+	err = validatePaging(i.config)
+	if err != nil {
+		i.provider.Io().Error(err.Error())
+		return
 	}
+	i.configured = true
 }
 
-func (i *Neo4jInput) OnInputConnectionOpened(_ sdk.InputConnection) {
-	panic("should never be called for input tools")
+func (i *Neo4jInput) OnInputConnectionOpened(connection sdk.InputConnection) {
+	i.hasInput = true
+	if !i.configured {
+		return
+	}
+	i.output.Open(i.outObjects.RecordInfo)
+	if i.provider.Environment().UpdateOnly() {
+		return
+	}
+
+	incomingInfo := connection.Metadata()
+	paramFields := i.config.ParamFields
+	if len(paramFields) == 0 {
+		for _, field := range incomingInfo.Fields() {
+			paramFields = append(paramFields, field.Name)
+		}
+	}
+	for _, field := range paramFields {
+		copier, err := util.FindFieldAndGenerateCopier(field, incomingInfo)
+		if err != nil {
+			i.provider.Io().Error(err.Error())
+			return
+		}
+		i.copiers = append(i.copiers, copier)
+	}
+
+	switch i.config.ParamMode {
+	case ``, `Record`:
+		i.params = make(map[string]interface{}, len(paramFields))
+	case `Batch`:
+		if i.config.ParamBatchSize <= 0 {
+			i.provider.Io().Error(`the parameter batch size must be greater than 0`)
+			return
+		}
+		i.batch = make([]map[string]interface{}, i.config.ParamBatchSize)
+		for index := range i.batch {
+			i.batch[index] = make(map[string]interface{}, len(paramFields))
+		}
+	default:
+		i.provider.Io().Error(fmt.Sprintf(`the ParamMode property '%v' is not valid, expected either 'Record' or 'Batch'`, i.config.ParamMode))
+		return
+	}
+
+	err := i.openSession()
+	if err != nil {
+		i.provider.Io().Error(err.Error())
+		return
+	}
+	i.doQuery = true
+	i.provider.Io().UpdateProgress(0.0)
+	i.output.UpdateProgress(0.0)
 }
 
-func (i *Neo4jInput) OnRecordPacket(_ sdk.InputConnection) {
-	panic("should never be called for input tools")
+func (i *Neo4jInput) OnRecordPacket(connection sdk.InputConnection) {
+	if !i.doQuery {
+		return
+	}
+
+	packet := connection.Read()
+	for packet.Next() {
+		copyFrom := packet.Record()
+		if i.batch != nil {
+			if i.currentBatchSize >= i.config.ParamBatchSize {
+				i.sendBatch()
+				if !i.doQuery {
+					return
+				}
+			}
+			if i.copyParams(copyFrom, i.batch[i.currentBatchSize]) {
+				i.currentBatchSize++
+			}
+			continue
+		}
+		if !i.copyParams(copyFrom, i.params) {
+			continue
+		}
+		err := i.runQuery(i.params)
+		if err != nil {
+			i.error(err.Error())
+			return
+		}
+	}
+	progress := connection.Progress()
+	i.provider.Io().UpdateProgress(progress)
+	i.output.UpdateProgress(progress)
 }
 
 func (i *Neo4jInput) OnComplete() {
+	if !i.configured {
+		return
+	}
+	if i.hasInput {
+		if i.doQuery && i.currentBatchSize > 0 {
+			i.sendBatch()
+		}
+		i.closeSession()
+		i.output.UpdateProgress(1.0)
+		i.provider.Io().UpdateProgress(1.0)
+		return
+	}
+
 	i.output.Open(i.outObjects.RecordInfo)
 	if i.provider.Environment().UpdateOnly() {
 		return
 	}
 
-	username, password := util.GetCredentials(i.config.ConnStr, i.config.Username, i.config.Password, i.provider)
-	driver, err := neo4j.NewDriver(i.config.ConnStr, neo4j.BasicAuth(username, password, ""))
+	err := i.openSession()
 	if err != nil {
-		i.provider.Io().Error(fmt.Sprintf(`expected no error but got: %v`, err.Error()))
+		i.provider.Io().Error(err.Error())
 		return
 	}
-	err = driver.VerifyConnectivity()
+	defer i.closeSession()
+
+	i.provider.Io().UpdateProgress(0.0)
+	i.output.UpdateProgress(0.0)
+
+	if countQuery := i.countQuery(); countQuery != `` {
+		i.totalRows, err = i.countRows(countQuery)
+		if err != nil {
+			i.provider.Io().Warn(fmt.Sprintf(`progress will not be reported because the count query failed: %v`, err.Error()))
+		}
+	}
+
+	err = i.runQuery(nil)
 	if err != nil {
 		i.provider.Io().Error(err.Error())
-		return
 	}
-	defer func() {
-		_ = driver.Close()
-	}()
+	i.output.UpdateProgress(1.0)
+	i.provider.Io().UpdateProgress(1.0)
+}
 
-	session := driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: i.config.Database})
-	defer func() {
-		_ = session.Close()
-	}()
+func (i *Neo4jInput) openSession() error {
+	var err error
+	i.driver, err = util.AcquireDriver(i.ctx, i.connectionConfig(), i.provider)
+	if err != nil {
+		return err
+	}
+	i.session = i.driver.NewSession(i.ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: i.config.Database})
+	return nil
+}
 
-	i.provider.Io().UpdateProgress(0.0)
-	i.output.UpdateProgress(0.0)
+func (i *Neo4jInput) closeSession() {
+	if i.session != nil {
+		_ = i.session.Close(i.ctx)
+	}
+	if i.driver != nil {
+		_ = util.ReleaseDriver(i.ctx, i.driver)
+	}
+}
 
-	_, err = session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		result, txErr := tx.Run(i.config.Query, nil)
+func (i *Neo4jInput) copyParams(copyFrom sdk.Record, copyTo map[string]interface{}) bool {
+	for _, copyData := range i.copiers {
+		err := copyData(copyFrom, copyTo)
+		if err != nil {
+			i.provider.Io().Error(fmt.Sprintf(`skipping a parameter record: %v`, err.Error()))
+			return false
+		}
+	}
+	return true
+}
+
+func (i *Neo4jInput) sendBatch() {
+	err := i.runQuery(map[string]interface{}{`batch`: i.batch[:i.currentBatchSize]})
+	if err != nil {
+		i.error(err.Error())
+		return
+	}
+	i.currentBatchSize = 0
+}
+
+func (i *Neo4jInput) runQuery(params map[string]interface{}) error {
+	if i.limitReached() {
+		return nil
+	}
+	if i.config.PageMode != `` {
+		return i.runPagedQuery(params)
+	}
+	_, err := i.session.ExecuteRead(i.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(i.ctx, i.config.Query, params)
 		if txErr != nil {
 			return nil, txErr
 		}
-		for result.Next() {
-			record := result.Record()
-			for _, transferFunc := range i.outObjects.TransferFuncs {
-				err = transferFunc(record)
-				if err != nil {
-					i.provider.Io().Error(err.Error())
-					return nil, err
-				}
+		for result.Next(i.ctx) {
+			if i.limitReached() {
+				break
+			}
+			txErr = i.writeRecord(result.Record())
+			if txErr != nil {
+				return nil, txErr
 			}
-			i.output.Write()
 		}
 
 		if txErr = result.Err(); txErr != nil {
 			return nil, txErr
 		}
 
-		return result.Consume()
+		return result.Consume(i.ctx)
 	})
-	if err != nil {
-		i.provider.Io().Error(err.Error())
+	return err
+}
+
+func (i *Neo4jInput) writeRecord(record *neo4j.Record) error {
+	for _, transferFunc := range i.outObjects.TransferFuncs {
+		err := transferFunc(record)
+		if err != nil {
+			i.provider.Io().Error(err.Error())
+			return err
+		}
+	}
+	i.output.Write()
+	i.rowsRead++
+	i.reportProgress()
+	return nil
+}
+
+func (i *Neo4jInput) error(msg string) {
+	i.doQuery = false
+	i.provider.Io().Error(msg)
+}
+
+func (i *Neo4jInput) connectionConfig() util.ConnectionConfig {
+	return util.ConnectionConfig{
+		Credentials: util.CredentialSource{
+			Url:        i.config.ConnStr,
+			Username:   i.config.Username,
+			Password:   i.config.Password,
+			Provider:   i.config.CredentialProvider,
+			Precedence: i.config.CredentialPrecedence,
+			File:       i.config.CredentialFile,
+			AuthType:   i.config.AuthType,
+			Realm:      i.config.Realm,
+		},
+		TlsCaFile:                           i.config.TlsCaFile,
+		TlsSkipVerify:                       i.config.TlsSkipVerify,
+		MaxConnectionPoolSize:               i.config.MaxConnectionPoolSize,
+		MaxTransactionRetrySeconds:          i.config.MaxTransactionRetrySeconds,
+		ConnectionAcquisitionTimeoutSeconds: i.config.ConnectionAcquisitionTimeoutSeconds,
+		UserAgent:                           i.config.UserAgent,
 	}
-	i.output.UpdateProgress(1.0)
-	i.provider.Io().UpdateProgress(1.0)
 }
@@ -0,0 +1,108 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"regexp"
+	"strings"
+)
+
+var skipOrLimitClause = regexp.MustCompile(`(?i)(^|\s)(SKIP|LIMIT)\s`)
+var orderByClause = regexp.MustCompile(`(?i)(^|\s)ORDER\s+BY\s`)
+
+func SkipPagedQuery(query string) (string, error) {
+	trimmed := strings.TrimSpace(query)
+	if strings.HasSuffix(trimmed, `;`) {
+		return ``, errors.New(`skip paging cannot be used with a query that ends in ';'`)
+	}
+	if skipOrLimitClause.MatchString(trimmed) {
+		return ``, errors.New(`skip paging adds its own SKIP and LIMIT, so the query cannot contain SKIP or LIMIT; use Keyset paging instead`)
+	}
+	if !orderByClause.MatchString(trimmed) {
+		return ``, errors.New(`skip paging requires the query to end with an ORDER BY that gives the rows a stable order; use Keyset paging for queries that cannot be ordered`)
+	}
+	return trimmed + "\nSKIP $offset LIMIT $pageSize", nil
+}
+
+func validatePaging(config Configuration) error {
+	switch config.PageMode {
+	case ``:
+		return nil
+	case `Skip`, `Keyset`:
+	default:
+		return fmt.Errorf(`the PageMode property '%v' is not valid, expected either 'Skip' or 'Keyset'`, config.PageMode)
+	}
+	if config.PageSize <= 0 {
+		return errors.New(`the page size must be greater than 0`)
+	}
+	if config.PageMode == `Keyset` && config.PageKey == `` {
+		return errors.New(`keyset paging requires a page key`)
+	}
+	if config.PageMode == `Skip` {
+		_, err := SkipPagedQuery(config.Query)
+		return err
+	}
+	return nil
+}
+
+func (i *Neo4jInput) runPagedQuery(params map[string]interface{}) error {
+	query := i.config.Query
+	if i.config.PageMode == `Skip` {
+		var err error
+		query, err = SkipPagedQuery(query)
+		if err != nil {
+			return err
+		}
+	}
+	pageParams := make(map[string]interface{}, len(params)+3)
+	for key, value := range params {
+		pageParams[key] = value
+	}
+	pageParams[`pageSize`] = int64(i.config.PageSize)
+	pageParams[`offset`] = int64(0)
+	pageParams[`lastKey`] = nil
+
+	for !i.limitReached() {
+		records, err := i.readPage(query, pageParams)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if i.limitReached() {
+				return nil
+			}
+			err = i.writeRecord(record)
+			if err != nil {
+				return err
+			}
+		}
+		if len(records) < i.config.PageSize {
+			return nil
+		}
+		if i.config.PageMode == `Skip` {
+			pageParams[`offset`] = pageParams[`offset`].(int64) + int64(len(records))
+			continue
+		}
+		lastKey, ok := records[len(records)-1].Get(i.config.PageKey)
+		if !ok {
+			return fmt.Errorf(`the page key '%v' was not returned by the query`, i.config.PageKey)
+		}
+		pageParams[`lastKey`] = lastKey
+	}
+	return nil
+}
+
+func (i *Neo4jInput) readPage(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	records, err := i.session.ExecuteRead(i.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(i.ctx, query, params)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return result.Collect(i.ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records.([]*neo4j.Record), nil
+}
@@ -0,0 +1,104 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"strconv"
+)
+
+type extractPoint func(record *neo4j.Record) (interface{}, error)
+
+type point struct {
+	x     float64
+	y     float64
+	z     float64
+	hasZ  bool
+	srid  uint32
+	empty bool
+}
+
+func toPoint(value interface{}, field Field) (point, error) {
+	switch typed := value.(type) {
+	case nil:
+		return point{empty: true}, nil
+	case neo4j.Point2D:
+		return point{x: typed.X, y: typed.Y, srid: typed.SpatialRefId}, nil
+	case *neo4j.Point2D:
+		return point{x: typed.X, y: typed.Y, srid: typed.SpatialRefId}, nil
+	case neo4j.Point3D:
+		return point{x: typed.X, y: typed.Y, z: typed.Z, hasZ: true, srid: typed.SpatialRefId}, nil
+	case *neo4j.Point3D:
+		return point{x: typed.X, y: typed.Y, z: typed.Z, hasZ: true, srid: typed.SpatialRefId}, nil
+	default:
+		return point{}, fmt.Errorf(`value %v is not a Point for field %v, but is %T`, value, field.Name, value)
+	}
+}
+
+func pointTransferFunc(iterator *pathIterator, field Field, extract extractPoint) (GetValueFunc, error) {
+	element, ok := iterator.NextField()
+	if !ok {
+		return nil, fmt.Errorf(`the path for field %v ends in a Point and not in a property data type`, field.Name)
+	}
+
+	var getValue func(point) interface{}
+	switch element.Key {
+	case `X`:
+		getValue = func(p point) interface{} { return p.x }
+	case `Y`:
+		getValue = func(p point) interface{} { return p.y }
+	case `Z`:
+		getValue = func(p point) interface{} {
+			if !p.hasZ {
+				return nil
+			}
+			return p.z
+		}
+	case `SRID`:
+		getValue = func(p point) interface{} { return int64(p.srid) }
+	case `ToWKT`:
+		getValue = func(p point) interface{} { return PointToWKT(p.x, p.y, p.z, p.hasZ) }
+	case `ToGeoJSON`:
+		getValue = func(p point) interface{} { return PointToGeoJSON(p.x, p.y, p.z, p.hasZ) }
+	default:
+		return nil, fmt.Errorf(`field %v has an invalid key '%v' for Point`, field.Name, element.Key)
+	}
+
+	return func(record *neo4j.Record) (interface{}, error) {
+		value, err := extract(record)
+		if err != nil {
+			return nil, err
+		}
+		extracted, err := toPoint(value, field)
+		if err != nil {
+			return nil, err
+		}
+		if extracted.empty {
+			return nil, nil
+		}
+		return getValue(extracted), nil
+	}, nil
+}
+
+func PointToWKT(x, y, z float64, hasZ bool) string {
+	if hasZ {
+		return fmt.Sprintf(`POINT Z (%v %v %v)`, formatCoordinate(x), formatCoordinate(y), formatCoordinate(z))
+	}
+	return fmt.Sprintf(`POINT (%v %v)`, formatCoordinate(x), formatCoordinate(y))
+}
+
+func PointToGeoJSON(x, y, z float64, hasZ bool) string {
+	coordinates := []float64{x, y}
+	if hasZ {
+		coordinates = append(coordinates, z)
+	}
+	geoJson, _ := json.Marshal(map[string]interface{}{
+		`type`:        `Point`,
+		`coordinates`: coordinates,
+	})
+	return string(geoJson)
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,66 @@
+package input
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func WrapCountQuery(query string) string {
+	return fmt.Sprintf("CALL {\n%v\n} RETURN count(*)", query)
+}
+
+func (i *Neo4jInput) countQuery() string {
+	if i.config.CountQuery != `` {
+		return i.config.CountQuery
+	}
+	if i.config.AutoCount {
+		return WrapCountQuery(i.config.Query)
+	}
+	return ``
+}
+
+func (i *Neo4jInput) countRows(query string) (int64, error) {
+	count, err := i.session.ExecuteRead(i.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(i.ctx, query, nil)
+		if txErr != nil {
+			return nil, txErr
+		}
+		record, txErr := result.Single(i.ctx)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return record.Values[0], nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	total, ok := count.(int64)
+	if !ok {
+		return 0, fmt.Errorf(`the count query returned %v instead of an integer`, count)
+	}
+	if limit := int64(i.config.RowLimit); limit > 0 && total > limit {
+		total = limit
+	}
+	return total, nil
+}
+
+func (i *Neo4jInput) limitReached() bool {
+	return i.config.RowLimit > 0 && i.rowsRead >= int64(i.config.RowLimit)
+}
+
+func (i *Neo4jInput) reportProgress() {
+	if i.totalRows <= 0 {
+		return
+	}
+	percent := int(i.rowsRead * 100 / i.totalRows)
+	if percent > 100 {
+		percent = 100
+	}
+	if percent == i.lastPercent {
+		return
+	}
+	i.lastPercent = percent
+	progress := float64(percent) / 100
+	i.provider.Io().UpdateProgress(progress)
+	i.output.UpdateProgress(progress)
+}
@@ -1,7 +1,7 @@
 package input_test
 
 import (
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/graphyx/input"
 	"testing"
 	"time"
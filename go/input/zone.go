@@ -0,0 +1,44 @@
+package input
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"time"
+)
+
+func zoneColumnSuffix(zoneColumns string) (string, error) {
+	switch zoneColumns {
+	case ``:
+		return ``, nil
+	case `Offset`:
+		return `_Offset`, nil
+	case `Name`:
+		return `_Zone`, nil
+	default:
+		return ``, fmt.Errorf(`the zone column option '%v' is not valid, expected 'Offset' or 'Name'`, zoneColumns)
+	}
+}
+
+func zoneTransferFunc(getValueFunc GetValueFunc, zoneColumns string) GetValueFunc {
+	return func(record *neo4j.Record) (interface{}, error) {
+		value, err := getValueFunc(record)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		var zoned time.Time
+		switch typed := value.(type) {
+		case neo4j.Time:
+			zoned = typed.Time()
+		case time.Time:
+			zoned = typed
+		default:
+			return nil, nil
+		}
+		if zoneColumns == `Name` {
+			if name := zoned.Location().String(); name != `Offset` && name != `` {
+				return name, nil
+			}
+		}
+		return zoned.Format(`-07:00`), nil
+	}
+}
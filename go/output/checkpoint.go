@@ -0,0 +1,388 @@
+package output
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+var ErrCheckpointConfigChanged = errors.New(`the checkpoint was written by a different tool configuration`)
+
+type CheckpointStore interface {
+	Load(hash string) (CheckpointState, error)
+	Save(hash string, state CheckpointState) error
+	Clear(hash string) error
+}
+
+// RecordRange covers the input records from Start up to, but not including, End.
+type RecordRange struct {
+	Start int
+	End   int
+}
+
+// CheckpointState lists the input records that have been written. Every record before Records is
+// written, and Ranges holds the written records after that, which appear when batches finish out of
+// order or a writer fails while other batches are still committing.
+type CheckpointState struct {
+	Records int
+	Ranges  []RecordRange
+}
+
+func (s *CheckpointState) Add(ranges []RecordRange) {
+	all := append(append([]RecordRange{}, s.Ranges...), ranges...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+	s.Ranges = nil
+	for _, item := range all {
+		if item.Start <= s.Records {
+			if item.End > s.Records {
+				s.Records = item.End
+			}
+			continue
+		}
+		last := len(s.Ranges) - 1
+		if last >= 0 && item.Start <= s.Ranges[last].End {
+			if item.End > s.Ranges[last].End {
+				s.Ranges[last].End = item.End
+			}
+			continue
+		}
+		s.Ranges = append(s.Ranges, item)
+	}
+}
+
+func (s CheckpointState) Contains(record int) bool {
+	if record < s.Records {
+		return true
+	}
+	for _, item := range s.Ranges {
+		if record >= item.Start && record < item.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (s CheckpointState) Written() int {
+	written := s.Records
+	for _, item := range s.Ranges {
+		written += item.End - item.Start
+	}
+	return written
+}
+
+func (s CheckpointState) End() int {
+	if len(s.Ranges) == 0 {
+		return s.Records
+	}
+	return s.Ranges[len(s.Ranges)-1].End
+}
+
+// CheckpointRecordField holds each row's position in the input while checkpoints are on. Alteryx field
+// names cannot be empty, so it never collides with a field.
+const CheckpointRecordField = ``
+
+func RecordRanges(rows []map[string]interface{}) []RecordRange {
+	records := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if record, ok := row[CheckpointRecordField].(int); ok {
+			records = append(records, record)
+		}
+	}
+	sort.Ints(records)
+	var ranges []RecordRange
+	for _, record := range records {
+		ranges = AppendRecord(ranges, record)
+	}
+	return ranges
+}
+
+func AppendRecord(ranges []RecordRange, record int) []RecordRange {
+	last := len(ranges) - 1
+	if last >= 0 && ranges[last].End == record {
+		ranges[last].End++
+		return ranges
+	}
+	return append(ranges, RecordRange{Start: record, End: record + 1})
+}
+
+type Checkpoint struct {
+	State CheckpointState
+	store CheckpointStore
+	hash  string
+}
+
+func NewCheckpoint(store CheckpointStore, hash string) (*Checkpoint, error) {
+	state, err := store.Load(hash)
+	return &Checkpoint{State: state, store: store, hash: hash}, err
+}
+
+// Commit records the input records of a finished batch. Stores that write the ranges inside the batch's
+// own transaction have already persisted them, so only the in-memory state is updated.
+func (c *Checkpoint) Commit(ranges []RecordRange, persisted bool) error {
+	c.State.Add(ranges)
+	if persisted {
+		return nil
+	}
+	return c.store.Save(c.hash, c.State)
+}
+
+func (c *Checkpoint) Clear() error {
+	return c.store.Clear(c.hash)
+}
+
+func ConfigHash(config Configuration) (string, error) {
+	config.Username = ``
+	config.Password = ``
+	config.CredentialProvider = ``
+	config.CredentialFile = ``
+	config.CredentialPrecedence = ``
+	config.Writers = 0
+	content, err := json.Marshal(config)
+	if err != nil {
+		return ``, err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+type fileCheckpointContent struct {
+	ConfigHash string
+	Records    int
+	Ranges     []RecordRange
+	Updated    time.Time
+}
+
+type FileCheckpointStore struct {
+	Path string
+}
+
+func (s *FileCheckpointStore) Load(hash string) (CheckpointState, error) {
+	content, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return CheckpointState{}, nil
+	}
+	if err != nil {
+		return CheckpointState{}, err
+	}
+	var checkpoint fileCheckpointContent
+	err = json.Unmarshal(content, &checkpoint)
+	if err != nil {
+		return CheckpointState{}, fmt.Errorf(`error reading checkpoint file %v: %v`, s.Path, err.Error())
+	}
+	if checkpoint.ConfigHash != hash {
+		return CheckpointState{}, ErrCheckpointConfigChanged
+	}
+	return CheckpointState{Records: checkpoint.Records, Ranges: checkpoint.Ranges}, nil
+}
+
+func (s *FileCheckpointStore) Save(hash string, state CheckpointState) error {
+	content, err := json.Marshal(fileCheckpointContent{ConfigHash: hash, Records: state.Records, Ranges: state.Ranges, Updated: time.Now()})
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+`.*`)
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(content)
+	closeErr := temp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return err
+	}
+	return os.Rename(temp.Name(), s.Path)
+}
+
+func (s *FileCheckpointStore) Clear(_ string) error {
+	err := os.Remove(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// nodeCheckpointStore keeps the saved state on a GraphyxCheckpoint node. Batches written in a single
+// transaction also create a GraphyxCheckpointRange node in that transaction, so a batch can never be
+// committed without its checkpoint. Saving the state removes the range nodes it already covers.
+type nodeCheckpointStore struct {
+	ctx     context.Context
+	session neo4j.SessionWithContext
+}
+
+const saveNodeCheckpointQuery = `MERGE (c:GraphyxCheckpoint {configHash: $hash})
+SET c.records = $records, c.starts = $starts, c.ends = $ends, c.updated = datetime()
+WITH c
+OPTIONAL MATCH (r:GraphyxCheckpointRange {configHash: $hash})
+WHERE all(i IN range(0, size(r.starts) - 1) WHERE r.ends[i] <= $records OR any(j IN range(0, size($starts) - 1) WHERE $starts[j] <= r.starts[i] AND r.ends[i] <= $ends[j]))
+DELETE r`
+
+func (s *nodeCheckpointStore) Load(hash string) (CheckpointState, error) {
+	state, err := s.session.ExecuteRead(s.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		state := CheckpointState{}
+		result, txErr := tx.Run(s.ctx, `MATCH (c:GraphyxCheckpoint {configHash: $hash}) RETURN c.records AS records, c.starts AS starts, c.ends AS ends
+UNION ALL
+MATCH (c:GraphyxCheckpointRange {configHash: $hash}) RETURN 0 AS records, c.starts AS starts, c.ends AS ends`, map[string]interface{}{`hash`: hash})
+		if txErr != nil {
+			return state, txErr
+		}
+		for result.Next(s.ctx) {
+			record := result.Record()
+			records, _ := record.Get(`records`)
+			starts, _ := record.Get(`starts`)
+			ends, _ := record.Get(`ends`)
+			count, _ := records.(int64)
+			state.Add([]RecordRange{{Start: 0, End: int(count)}})
+			state.Add(decodeRanges(starts, ends))
+		}
+		return state, result.Err()
+	})
+	if err != nil {
+		return CheckpointState{}, err
+	}
+	return state.(CheckpointState), nil
+}
+
+func (s *nodeCheckpointStore) Save(hash string, state CheckpointState) error {
+	starts, ends := encodeRanges(state.Ranges)
+	return s.run(saveNodeCheckpointQuery, map[string]interface{}{`hash`: hash, `records`: state.Records, `starts`: starts, `ends`: ends})
+}
+
+func (s *nodeCheckpointStore) Clear(hash string) error {
+	return s.run(`MATCH (c) WHERE (c:GraphyxCheckpoint OR c:GraphyxCheckpointRange) AND c.configHash = $hash DELETE c`, map[string]interface{}{`hash`: hash})
+}
+
+func (s *nodeCheckpointStore) commitInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, hash string, ranges []RecordRange) error {
+	starts, ends := encodeRanges(ranges)
+	result, err := tx.Run(ctx, `CREATE (:GraphyxCheckpointRange {configHash: $hash, starts: $starts, ends: $ends})`, map[string]interface{}{`hash`: hash, `starts`: starts, `ends`: ends})
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume(ctx)
+	return err
+}
+
+func (s *nodeCheckpointStore) run(query string, params map[string]interface{}) error {
+	_, err := s.session.ExecuteWrite(s.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, txErr := tx.Run(s.ctx, query, params)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return result.Consume(s.ctx)
+	})
+	return err
+}
+
+func encodeRanges(ranges []RecordRange) ([]int, []int) {
+	starts := make([]int, len(ranges))
+	ends := make([]int, len(ranges))
+	for index, item := range ranges {
+		starts[index] = item.Start
+		ends[index] = item.End
+	}
+	return starts, ends
+}
+
+func decodeRanges(starts interface{}, ends interface{}) []RecordRange {
+	startList, _ := starts.([]interface{})
+	endList, _ := ends.([]interface{})
+	var ranges []RecordRange
+	for index := 0; index < len(startList) && index < len(endList); index++ {
+		start, _ := startList[index].(int64)
+		end, _ := endList[index].(int64)
+		ranges = append(ranges, RecordRange{Start: int(start), End: int(end)})
+	}
+	return ranges
+}
+
+func (o *Neo4jOutput) openCheckpoint() error {
+	var store CheckpointStore
+	switch o.config.Checkpoint {
+	case ``:
+		return nil
+	case `File`:
+		if o.config.CheckpointFile == `` {
+			return errors.New(`no CheckpointFile was provided for the 'File' checkpoint`)
+		}
+		store = &FileCheckpointStore{Path: o.config.CheckpointFile}
+	case `Node`:
+		store = &nodeCheckpointStore{ctx: o.ctx, session: o.session}
+	default:
+		return fmt.Errorf(`the Checkpoint property '%v' is not valid, expected 'File' or 'Node'`, o.config.Checkpoint)
+	}
+	if o.config.DryRun {
+		return nil
+	}
+	if o.config.PartitionByKey && o.config.Writers > 1 {
+		return errors.New(`checkpoints cannot be used with PartitionByKey because partitioned batches do not follow the input order`)
+	}
+	hash, err := ConfigHash(o.config)
+	if err != nil {
+		return err
+	}
+	o.checkpoint, err = NewCheckpoint(store, hash)
+	if errors.Is(err, ErrCheckpointConfigChanged) {
+		o.provider.Io().Warn(`the checkpoint was written by a different tool configuration and was ignored; all records will be written`)
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf(`error loading checkpoint: %v`, err.Error())
+	}
+	if written := o.checkpoint.State.Written(); written > 0 {
+		o.provider.Io().Info(fmt.Sprintf(`resuming from checkpoint: skipping %v records that were already written`, written))
+	}
+	return nil
+}
+
+// skipRecord counts every input record and reports whether the checkpoint shows it was already written.
+func (o *Neo4jOutput) skipRecord() bool {
+	if o.checkpoint == nil {
+		return false
+	}
+	o.recordIndex++
+	return o.checkpoint.State.Contains(o.recordIndex - 1)
+}
+
+// transactionalCheckpoint lists the input records in rows, and returns the store that records them inside
+// the transaction that writes them. Every transaction is recorded this way, including the halves of a batch
+// that was split by retries or bisection, so rows that committed are never written again on a rerun.
+func (o *Neo4jOutput) transactionalCheckpoint(rows []map[string]interface{}) (*nodeCheckpointStore, []RecordRange) {
+	if o.checkpoint == nil {
+		return nil, nil
+	}
+	store, _ := o.checkpoint.store.(*nodeCheckpointStore)
+	return store, RecordRanges(rows)
+}
+
+func (o *Neo4jOutput) commitCheckpoint(ranges []RecordRange, persisted bool) {
+	if o.checkpoint == nil || len(ranges) == 0 {
+		return
+	}
+	err := o.checkpoint.Commit(ranges, persisted)
+	if err != nil {
+		o.error(fmt.Sprintf(`error saving checkpoint: %v`, err.Error()))
+	}
+}
+
+func (o *Neo4jOutput) closeCheckpoint() {
+	if o.checkpoint == nil || !o.doExport {
+		return
+	}
+	if end := o.checkpoint.State.End(); o.recordIndex < end {
+		o.provider.Io().Warn(fmt.Sprintf(`the input ended %v records before the end of the checkpoint; check that the input is in the same order as the interrupted run`, end-o.recordIndex))
+	}
+	err := o.checkpoint.Clear()
+	if err != nil {
+		o.provider.Io().Warn(fmt.Sprintf(`error clearing checkpoint: %v`, err.Error()))
+	}
+}
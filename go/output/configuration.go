@@ -7,9 +7,14 @@ import (
 )
 
 type NodeConfig struct {
-	Label      string
-	IdFields   []string
-	PropFields []string
+	Label            string
+	IdFields         []string
+	PropFields       []string
+	CreatePropFields []string
+	MatchPropFields  []string
+	ExtraLabels      []string
+	PropertyNames    map[string]string
+	WriteMode        string
 }
 
 type RelationshipConfig struct {
@@ -22,24 +27,52 @@ type RelationshipConfig struct {
 	Label              string
 	PropFields         []string
 	IdFields           []string
+	CreatePropFields   []string
+	MatchPropFields    []string
+	PropertyNames      map[string]string
+	WriteMode          string
+	MergeEndpoints     bool
 }
 
 func NodeQuery(config *NodeConfig) (string, error) {
 	if config.Label == `` {
 		return ``, errors.New(`label cannot be blank`)
 	}
+	writeMode := config.WriteMode
+	if writeMode == `` {
+		writeMode = `Merge`
+		if len(config.IdFields) == 0 {
+			writeMode = `Create`
+		}
+	}
+	if writeMode != `Create` && len(config.IdFields) == 0 {
+		return ``, fmt.Errorf(`the %v write mode requires at least one node ID field`, writeMode)
+	}
+
 	builder := &strings.Builder{}
 	builder.WriteString("UNWIND $batch AS row\n")
-	if len(config.IdFields) == 0 {
+	switch writeMode {
+	case `Create`:
 		createNodeClause(builder, config)
-		return builder.String(), nil
-	}
-	mergeNodeClause(builder, config)
-	if len(config.PropFields) == 0 {
-		return builder.String(), nil
+	case `Merge`:
+		nodeClause(builder, `MERGE`, config)
+		setClauses(builder, `newNode`, config.PropertyNames,
+			setClause{keyword: `ON CREATE SET`, props: concatFields(config.PropFields, config.CreatePropFields), labels: config.ExtraLabels},
+			setClause{keyword: `ON MATCH SET`, props: concatFields(config.PropFields, config.MatchPropFields), labels: config.ExtraLabels},
+		)
+	case `UpdateOnly`:
+		nodeClause(builder, `MATCH`, config)
+		setClauses(builder, `newNode`, config.PropertyNames,
+			setClause{keyword: `SET`, props: concatFields(config.PropFields, config.MatchPropFields), labels: config.ExtraLabels},
+		)
+	case `InsertOnly`:
+		nodeClause(builder, `MERGE`, config)
+		setClauses(builder, `newNode`, config.PropertyNames,
+			setClause{keyword: `ON CREATE SET`, props: concatFields(config.PropFields, config.CreatePropFields), labels: config.ExtraLabels},
+		)
+	default:
+		return ``, invalidWriteMode(writeMode)
 	}
-	onCreateSetQuery(builder, config.PropFields, `newNode`)
-
 	return builder.String(), nil
 }
 
@@ -47,33 +80,96 @@ func RelationshipQuery(config *RelationshipConfig) (string, error) {
 	if config.Label == `` {
 		return ``, errors.New(`label cannot be blank`)
 	}
-	if config.LeftLabel == `` {
-		return ``, errors.New(`left node label cannot be blank`)
-	}
-	if config.RightLabel == `` {
-		return ``, errors.New(`right node label cannot be blank`)
+	err := validateEndpoints(config)
+	if err != nil {
+		return ``, err
 	}
-	if len(config.LeftNeo4jFields) != len(config.LeftAlteryxFields) {
-		return ``, errors.New(`the number of left-node Neo4j fields does not match the number of left-node Alteryx fields`)
+
+	endpointClause := `MATCH`
+	if config.MergeEndpoints {
+		endpointClause = `MERGE`
 	}
-	if len(config.RightNeo4jFields) != len(config.RightAlteryxFields) {
-		return ``, errors.New(`the number of right-node Neo4j fields does not match the number of right-node Alteryx fields`)
+	writeMode := config.WriteMode
+	if writeMode == `` {
+		writeMode = `Merge`
 	}
 
 	builder := &strings.Builder{}
 	builder.WriteString("UNWIND $batch AS row\n")
-	matchNode(builder, escapeName(config.LeftLabel), config.LeftAlteryxFields, config.LeftNeo4jFields, `left`)
-	matchNode(builder, escapeName(config.RightLabel), config.RightAlteryxFields, config.RightNeo4jFields, `right`)
-	mergeRelClause(builder, config)
-	if len(config.PropFields) == 0 {
-		return builder.String(), nil
+	matchNode(builder, endpointClause, escapeName(config.LeftLabel), config.LeftAlteryxFields, config.LeftNeo4jFields, `left`)
+	matchNode(builder, endpointClause, escapeName(config.RightLabel), config.RightAlteryxFields, config.RightNeo4jFields, `right`)
+	switch writeMode {
+	case `Create`:
+		relClause(builder, `CREATE`, config.Label, config.PropertyNames, concatFields(config.IdFields, config.PropFields, config.CreatePropFields))
+	case `Merge`:
+		relClause(builder, `MERGE`, config.Label, config.PropertyNames, config.IdFields)
+		setClauses(builder, `newRel`, config.PropertyNames,
+			setClause{keyword: `ON CREATE SET`, props: concatFields(config.PropFields, config.CreatePropFields)},
+			setClause{keyword: `ON MATCH SET`, props: concatFields(config.PropFields, config.MatchPropFields)},
+		)
+	case `UpdateOnly`:
+		relClause(builder, `MATCH`, config.Label, config.PropertyNames, config.IdFields)
+		setClauses(builder, `newRel`, config.PropertyNames,
+			setClause{keyword: `SET`, props: concatFields(config.PropFields, config.MatchPropFields)},
+		)
+	case `InsertOnly`:
+		relClause(builder, `MERGE`, config.Label, config.PropertyNames, config.IdFields)
+		setClauses(builder, `newRel`, config.PropertyNames,
+			setClause{keyword: `ON CREATE SET`, props: concatFields(config.PropFields, config.CreatePropFields)},
+		)
+	default:
+		return ``, invalidWriteMode(writeMode)
+	}
+	return builder.String(), nil
+}
+
+func invalidWriteMode(writeMode string) error {
+	return fmt.Errorf(`the write mode '%v' is not valid, expected 'Create', 'Merge', 'UpdateOnly', or 'InsertOnly'`, writeMode)
+}
+
+func concatFields(fieldLists ...[]string) []string {
+	var fields []string
+	for _, fieldList := range fieldLists {
+		fields = append(fields, fieldList...)
 	}
-	onCreateSetQuery(builder, config.PropFields, `newRel`)
+	return fields
+}
+
+func UnmatchedEndpointsQuery(config *RelationshipConfig) (string, error) {
+	err := validateEndpoints(config)
+	if err != nil {
+		return ``, err
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString("UNWIND range(0, size($batch)-1) AS index\n")
+	builder.WriteString("WITH index, $batch[index] AS row\n")
+	matchNode(builder, `OPTIONAL MATCH`, escapeName(config.LeftLabel), config.LeftAlteryxFields, config.LeftNeo4jFields, `left`)
+	matchNode(builder, `OPTIONAL MATCH`, escapeName(config.RightLabel), config.RightAlteryxFields, config.RightNeo4jFields, `right`)
+	builder.WriteString("WITH index, count(left) AS lefts, count(right) AS rights\n")
+	builder.WriteString("WHERE lefts = 0 OR rights = 0\n")
+	builder.WriteString("RETURN index, lefts = 0 AS leftMissing, rights = 0 AS rightMissing")
 	return builder.String(), nil
 }
 
-func matchNode(builder *strings.Builder, label string, alteryxFields []string, neo4jFields []string, neo4jVariable string) {
-	builder.WriteString(fmt.Sprintf("MATCH (%v:`%v`{", neo4jVariable, label))
+func validateEndpoints(config *RelationshipConfig) error {
+	if config.LeftLabel == `` {
+		return errors.New(`left node label cannot be blank`)
+	}
+	if config.RightLabel == `` {
+		return errors.New(`right node label cannot be blank`)
+	}
+	if len(config.LeftNeo4jFields) != len(config.LeftAlteryxFields) {
+		return errors.New(`the number of left-node Neo4j fields does not match the number of left-node Alteryx fields`)
+	}
+	if len(config.RightNeo4jFields) != len(config.RightAlteryxFields) {
+		return errors.New(`the number of right-node Neo4j fields does not match the number of right-node Alteryx fields`)
+	}
+	return nil
+}
+
+func matchNode(builder *strings.Builder, clause string, label string, alteryxFields []string, neo4jFields []string, neo4jVariable string) {
+	builder.WriteString(fmt.Sprintf("%v (%v:`%v`{", clause, neo4jVariable, label))
 	for index, neo4jId := range neo4jFields {
 		neo4jId = escapeName(neo4jId)
 		alteryxId := escapeName(alteryxFields[index])
@@ -85,69 +181,105 @@ func matchNode(builder *strings.Builder, label string, alteryxFields []string, n
 	builder.WriteString("})\n")
 }
 
-func mergeNodeClause(builder *strings.Builder, config *NodeConfig) {
+func nodeClause(builder *strings.Builder, clause string, config *NodeConfig) {
 	label := escapeName(config.Label)
-	builder.WriteString(fmt.Sprintf("MERGE (newNode:`%v`{", label))
-	for index, id := range config.IdFields {
-		id = escapeName(id)
-		if index > 0 {
-			builder.WriteString(",")
-		}
-		builder.WriteString(fmt.Sprintf("`%v`:row.`%v`", id, id))
-	}
+	builder.WriteString(fmt.Sprintf("%v (newNode:`%v`{", clause, label))
+	writeProperties(builder, config.IdFields, config.PropertyNames)
 	builder.WriteString("})\n")
 }
 
 func createNodeClause(builder *strings.Builder, config *NodeConfig) {
-	label := escapeName(config.Label)
-	builder.WriteString(fmt.Sprintf("CREATE (newNode:`%v`{", label))
-	for index, id := range config.PropFields {
-		id = escapeName(id)
-		if index > 0 {
-			builder.WriteString(",")
+	builder.WriteString("CREATE (newNode")
+	writeLabels(builder, concatFields([]string{config.Label}, config.ExtraLabels))
+	builder.WriteString("{")
+	writeProperties(builder, concatFields(config.IdFields, config.PropFields, config.CreatePropFields), config.PropertyNames)
+	builder.WriteString("})")
+}
+
+type setClause struct {
+	keyword string
+	props   []string
+	labels  []string
+}
+
+func setClauses(builder *strings.Builder, neo4jVariable string, names map[string]string, clauses ...setClause) {
+	written := 0
+	for _, clause := range clauses {
+		if len(clause.props) == 0 && len(clause.labels) == 0 {
+			continue
+		}
+		if written > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(clause.keyword)
+		builder.WriteString(" ")
+		buildSetProperties(builder, clause.props, names, neo4jVariable)
+		if len(clause.labels) > 0 {
+			if len(clause.props) > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString(neo4jVariable)
+			writeLabels(builder, clause.labels)
 		}
-		builder.WriteString(fmt.Sprintf("`%v`:row.`%v`", id, id))
+		written++
 	}
-	builder.WriteString("})")
 }
 
-func onCreateSetQuery(builder *strings.Builder, props []string, neo4jVariable string) {
-	builder.WriteString("ON CREATE SET ")
-	buildSetProperties(builder, props, neo4jVariable)
-	builder.WriteString("\n")
-	builder.WriteString("ON MATCH SET ")
-	buildSetProperties(builder, props, neo4jVariable)
+func writeLabels(builder *strings.Builder, labels []string) {
+	for _, label := range labels {
+		builder.WriteString(fmt.Sprintf(":`%v`", escapeName(label)))
+	}
 }
 
-func buildSetProperties(builder *strings.Builder, props []string, neo4jVariable string) {
+func SplitLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.Split(value, `,`) {
+		label = strings.TrimSpace(label)
+		if label != `` {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func buildSetProperties(builder *strings.Builder, props []string, names map[string]string, neo4jVariable string) {
 	for index, prop := range props {
-		prop = escapeName(prop)
 		if index > 0 {
 			builder.WriteString(",")
 		}
-		builder.WriteString(fmt.Sprintf("%v.`%v`=row.`%v`", neo4jVariable, prop, prop))
+		builder.WriteString(fmt.Sprintf("%v.`%v`=row.`%v`", neo4jVariable, escapeName(propertyName(names, prop)), escapeName(prop)))
+	}
+}
+
+func writeProperties(builder *strings.Builder, fields []string, names map[string]string) {
+	for index, field := range fields {
+		if index > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(fmt.Sprintf("`%v`:row.`%v`", escapeName(propertyName(names, field)), escapeName(field)))
 	}
 }
 
+func propertyName(names map[string]string, field string) string {
+	if name, ok := names[field]; ok {
+		return name
+	}
+	return field
+}
+
 func escapeName(name string) string {
 	return strings.Replace(name, "`", "``", -1)
 }
 
-func mergeRelClause(builder *strings.Builder, config *RelationshipConfig) {
-	label := escapeName(config.Label)
-	builder.WriteString(fmt.Sprintf("MERGE (left)-[newRel:`%v`", label))
-	if len(config.IdFields) == 0 {
+func relClause(builder *strings.Builder, clause string, label string, names map[string]string, fields []string) {
+	label = escapeName(label)
+	builder.WriteString(fmt.Sprintf("%v (left)-[newRel:`%v`", clause, label))
+	if len(fields) == 0 {
 		builder.WriteString("]->(right)\n")
 		return
 	}
 
 	builder.WriteString(` {`)
-	for index, id := range config.IdFields {
-		id = escapeName(id)
-		if index > 0 {
-			builder.WriteString(",")
-		}
-		builder.WriteString(fmt.Sprintf("`%v`:row.`%v`", id, id))
-	}
+	writeProperties(builder, fields, names)
 	builder.WriteString("}]->(right)\n")
 }
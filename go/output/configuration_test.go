@@ -1,7 +1,14 @@
 package output_test
 
 import (
+	"context"
+	"errors"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/graphyx/output"
+	"github.com/tlarsendataguy/graphyx/util"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -329,3 +336,684 @@ func TestRelationshipWithIds(t *testing.T) {
 		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
 	}
 }
+
+func TestRelationshipQueryMergingEndpoints(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          `TestLabel`,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+		MergeEndpoints:     true,
+	}
+	query, _ := output.RelationshipQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (left:`TestLabel`{`id1`:row.`left1`})\n" +
+		"MERGE (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"MERGE (left)-[newRel:`TestRel`]->(right)\n"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestUnmatchedEndpointsQuery(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          "Test`Label",
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`, `left2`},
+		LeftNeo4jFields:    []string{`id1`, `id2`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+	}
+	query, err := output.UnmatchedEndpointsQuery(config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := "UNWIND range(0, size($batch)-1) AS index\n" +
+		"WITH index, $batch[index] AS row\n" +
+		"OPTIONAL MATCH (left:`Test``Label`{`id1`:row.`left1`,`id2`:row.`left2`})\n" +
+		"OPTIONAL MATCH (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"WITH index, count(left) AS lefts, count(right) AS rights\n" +
+		"WHERE lefts = 0 OR rights = 0\n" +
+		"RETURN index, lefts = 0 AS leftMissing, rights = 0 AS rightMissing"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestUnmatchedEndpointsQueryWithoutLeftLabel(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          ``,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+	}
+	query, err := output.UnmatchedEndpointsQuery(config)
+	if query != `` {
+		t.Fatalf(`expected '' but got '%v'`, query)
+	}
+	if err == nil {
+		t.Fatalf(`expected error but got nil`)
+	}
+	t.Logf(`%v`, err.Error())
+}
+
+func TestNodeQueryCreateMode(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:            `TestLabel`,
+		IdFields:         []string{`id1`},
+		PropFields:       []string{`prop1`},
+		CreatePropFields: []string{`created`},
+		MatchPropFields:  []string{`updated`},
+		WriteMode:        `Create`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"CREATE (newNode:`TestLabel`{`id1`:row.`id1`,`prop1`:row.`prop1`,`created`:row.`created`})"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryMergeModeWithSeparateProperties(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:            `TestLabel`,
+		IdFields:         []string{`id1`},
+		PropFields:       []string{`prop1`},
+		CreatePropFields: []string{`created`},
+		MatchPropFields:  []string{`updated`},
+		WriteMode:        `Merge`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"ON CREATE SET newNode.`prop1`=row.`prop1`,newNode.`created`=row.`created`\n" +
+		"ON MATCH SET newNode.`prop1`=row.`prop1`,newNode.`updated`=row.`updated`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryMergeModeWithOnlyMatchProperties(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:           `TestLabel`,
+		IdFields:        []string{`id1`},
+		MatchPropFields: []string{`updated`},
+		WriteMode:       `Merge`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"ON MATCH SET newNode.`updated`=row.`updated`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryUpdateOnlyMode(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:            `TestLabel`,
+		IdFields:         []string{`id1`},
+		PropFields:       []string{`prop1`},
+		CreatePropFields: []string{`created`},
+		MatchPropFields:  []string{`updated`},
+		WriteMode:        `UpdateOnly`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"SET newNode.`prop1`=row.`prop1`,newNode.`updated`=row.`updated`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryInsertOnlyMode(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:            `TestLabel`,
+		IdFields:         []string{`id1`},
+		PropFields:       []string{`prop1`},
+		CreatePropFields: []string{`created`},
+		MatchPropFields:  []string{`updated`},
+		WriteMode:        `InsertOnly`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"ON CREATE SET newNode.`prop1`=row.`prop1`,newNode.`created`=row.`created`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryMergeModeWithoutIds(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:      `TestLabel`,
+		PropFields: []string{`prop1`},
+		WriteMode:  `Merge`,
+	}
+	query, err := output.NodeQuery(config)
+	if query != `` {
+		t.Fatalf(`expected '' but got '%v'`, query)
+	}
+	if err == nil {
+		t.Fatalf(`expected error but got nil`)
+	}
+	t.Logf(`%v`, err.Error())
+}
+
+func TestNodeQueryInvalidWriteMode(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:     `TestLabel`,
+		IdFields:  []string{`id1`},
+		WriteMode: `Upsert`,
+	}
+	query, err := output.NodeQuery(config)
+	if query != `` {
+		t.Fatalf(`expected '' but got '%v'`, query)
+	}
+	if err == nil {
+		t.Fatalf(`expected error but got nil`)
+	}
+	t.Logf(`%v`, err.Error())
+}
+
+func TestRelationshipQueryCreateMode(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          `TestLabel`,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+		PropFields:         []string{`prop1`},
+		CreatePropFields:   []string{`created`},
+		WriteMode:          `Create`,
+	}
+	query, _ := output.RelationshipQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (left:`TestLabel`{`id1`:row.`left1`})\n" +
+		"MATCH (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"CREATE (left)-[newRel:`TestRel` {`prop1`:row.`prop1`,`created`:row.`created`}]->(right)\n"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestRelationshipQueryUpdateOnlyMode(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          `TestLabel`,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+		IdFields:           []string{`relId`},
+		PropFields:         []string{`prop1`},
+		MatchPropFields:    []string{`updated`},
+		WriteMode:          `UpdateOnly`,
+	}
+	query, _ := output.RelationshipQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (left:`TestLabel`{`id1`:row.`left1`})\n" +
+		"MATCH (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"MATCH (left)-[newRel:`TestRel` {`relId`:row.`relId`}]->(right)\n" +
+		"SET newRel.`prop1`=row.`prop1`,newRel.`updated`=row.`updated`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestRelationshipQueryInsertOnlyMode(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          `TestLabel`,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+		PropFields:         []string{`prop1`},
+		MatchPropFields:    []string{`updated`},
+		WriteMode:          `InsertOnly`,
+	}
+	query, _ := output.RelationshipQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (left:`TestLabel`{`id1`:row.`left1`})\n" +
+		"MATCH (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"MERGE (left)-[newRel:`TestRel`]->(right)\n" +
+		"ON CREATE SET newRel.`prop1`=row.`prop1`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryMergeModeWithExtraLabels(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:       `TestLabel`,
+		IdFields:    []string{`id1`},
+		PropFields:  []string{`prop1`},
+		ExtraLabels: []string{`Extra1`, `Extra2`},
+		WriteMode:   `Merge`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"ON CREATE SET newNode.`prop1`=row.`prop1`,newNode:`Extra1`:`Extra2`\n" +
+		"ON MATCH SET newNode.`prop1`=row.`prop1`,newNode:`Extra1`:`Extra2`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryUpdateOnlyModeWithOnlyExtraLabels(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:       `TestLabel`,
+		IdFields:    []string{`id1`},
+		ExtraLabels: []string{`Extra1`},
+		WriteMode:   `UpdateOnly`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (newNode:`TestLabel`{`id1`:row.`id1`})\n" +
+		"SET newNode:`Extra1`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryCreateModeWithExtraLabels(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:       `TestLabel`,
+		IdFields:    []string{`id1`},
+		ExtraLabels: []string{`Extra1`},
+		WriteMode:   `Create`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"CREATE (newNode:`TestLabel`:`Extra1`{`id1`:row.`id1`})"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestSplitLabels(t *testing.T) {
+	labels := output.SplitLabels(` Person, Employee,,Manager `)
+	if len(labels) != 3 {
+		t.Fatalf(`expected 3 labels but got %v`, len(labels))
+	}
+	if labels[0] != `Person` || labels[1] != `Employee` || labels[2] != `Manager` {
+		t.Fatalf(`expected [Person Employee Manager] but got %v`, labels)
+	}
+}
+
+func TestNodeQueryWithPropertyNames(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:         `TestLabel`,
+		IdFields:      []string{`Cust ID`},
+		PropFields:    []string{`Cust Name`, `prop1`},
+		PropertyNames: map[string]string{`Cust ID`: `customerId`, `Cust Name`: `name`},
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MERGE (newNode:`TestLabel`{`customerId`:row.`Cust ID`})\n" +
+		"ON CREATE SET newNode.`name`=row.`Cust Name`,newNode.`prop1`=row.`prop1`\n" +
+		"ON MATCH SET newNode.`name`=row.`Cust Name`,newNode.`prop1`=row.`prop1`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestNodeQueryCreateModeWithPropertyNames(t *testing.T) {
+	config := &output.NodeConfig{
+		Label:         `TestLabel`,
+		IdFields:      []string{`Cust ID`},
+		PropFields:    []string{`prop1`},
+		PropertyNames: map[string]string{`Cust ID`: `customerId`},
+		WriteMode:     `Create`,
+	}
+	query, _ := output.NodeQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"CREATE (newNode:`TestLabel`{`customerId`:row.`Cust ID`,`prop1`:row.`prop1`})"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestRelationshipQueryWithPropertyNames(t *testing.T) {
+	config := &output.RelationshipConfig{
+		LeftLabel:          `TestLabel`,
+		RightLabel:         `TestLabel`,
+		LeftAlteryxFields:  []string{`left1`},
+		LeftNeo4jFields:    []string{`id1`},
+		RightAlteryxFields: []string{`right1`},
+		RightNeo4jFields:   []string{`id1`},
+		Label:              `TestRel`,
+		IdFields:           []string{`Rel ID`},
+		PropFields:         []string{`Since Date`},
+		PropertyNames:      map[string]string{`Rel ID`: `relId`, `Since Date`: `since`},
+	}
+	query, _ := output.RelationshipQuery(config)
+	expected := "UNWIND $batch AS row\n" +
+		"MATCH (left:`TestLabel`{`id1`:row.`left1`})\n" +
+		"MATCH (right:`TestLabel`{`id1`:row.`right1`})\n" +
+		"MERGE (left)-[newRel:`TestRel` {`relId`:row.`Rel ID`}]->(right)\n" +
+		"ON CREATE SET newRel.`since`=row.`Since Date`\n" +
+		"ON MATCH SET newRel.`since`=row.`Since Date`"
+
+	if expected != query {
+		t.Fatalf("expected\n\n%v\n\nbut got\n\n%v", expected, query)
+	}
+}
+
+func TestIndexCoversTarget(t *testing.T) {
+	target := output.IndexTarget{Label: `Customer`, Properties: []string{`id`, `region`}}
+	covering := []output.NodeIndex{
+		{Type: `RANGE`, Label: `Customer`, Properties: []string{`id`}},
+		{Type: `RANGE`, Label: `Customer`, Properties: []string{`region`, `id`}},
+		{Type: `BTREE`, Label: `Customer`, Properties: []string{`id`, `region`}},
+	}
+	for _, index := range covering {
+		if !target.CoveredBy(index) {
+			t.Fatalf(`expected %v to cover %v`, index, target)
+		}
+	}
+	notCovering := []output.NodeIndex{
+		{Type: `RANGE`, Label: `Supplier`, Properties: []string{`id`}},
+		{Type: `RANGE`, Label: `Customer`, Properties: []string{`id`, `name`}},
+		{Type: `TEXT`, Label: `Customer`, Properties: []string{`id`}},
+		{Type: `LOOKUP`, Label: `Customer`},
+	}
+	for _, index := range notCovering {
+		if target.CoveredBy(index) {
+			t.Fatalf(`expected %v not to cover %v`, index, target)
+		}
+	}
+}
+
+func TestUniquenessConstraintCoversTarget(t *testing.T) {
+	target := output.IndexTarget{Label: `Customer`, Properties: []string{`id`, `region`}}
+	unique := []output.NodeIndex{
+		{Type: `UNIQUENESS`, Label: `Customer`, Properties: []string{`id`}},
+		{Type: `NODE_KEY`, Label: `Customer`, Properties: []string{`region`, `id`}},
+		{Type: `NODE_PROPERTY_UNIQUENESS`, Label: `Customer`, Properties: []string{`id`}},
+	}
+	for _, constraint := range unique {
+		if !target.UniqueBy(constraint) {
+			t.Fatalf(`expected %v to make %v unique`, constraint, target)
+		}
+	}
+	notUnique := []output.NodeIndex{
+		{Type: `RANGE`, Label: `Customer`, Properties: []string{`id`}},
+		{Type: `UNIQUENESS`, Label: `Supplier`, Properties: []string{`id`}},
+		{Type: `UNIQUENESS`, Label: `Customer`, Properties: []string{`id`, `name`}},
+		{Type: `NODE_PROPERTY_EXISTENCE`, Label: `Customer`, Properties: []string{`id`}},
+	}
+	for _, constraint := range notUnique {
+		if target.UniqueBy(constraint) {
+			t.Fatalf(`expected %v not to make %v unique`, constraint, target)
+		}
+	}
+}
+
+func TestCreateIndexQueries(t *testing.T) {
+	single := output.IndexTarget{Label: `Customer`, Properties: []string{`id`}}
+	composite := output.IndexTarget{Label: "Cust`omer", Properties: []string{`id`, `region`}}
+
+	if expected := "CREATE INDEX IF NOT EXISTS FOR (n:`Customer`) ON (n.`id`)"; output.CreateIndexQuery(single) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, output.CreateIndexQuery(single))
+	}
+	if expected := "CREATE INDEX IF NOT EXISTS FOR (n:`Cust``omer`) ON (n.`id`, n.`region`)"; output.CreateIndexQuery(composite) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, output.CreateIndexQuery(composite))
+	}
+	if expected := "CREATE CONSTRAINT IF NOT EXISTS FOR (n:`Customer`) REQUIRE n.`id` IS UNIQUE"; output.CreateConstraintQuery(single) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, output.CreateConstraintQuery(single))
+	}
+	if expected := "CREATE CONSTRAINT IF NOT EXISTS FOR (n:`Cust``omer`) REQUIRE (n.`id`, n.`region`) IS UNIQUE"; output.CreateConstraintQuery(composite) != expected {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, output.CreateConstraintQuery(composite))
+	}
+}
+
+func TestPartitionIndexKeepsKeysTogether(t *testing.T) {
+	keyFields := []string{`ID`, `Region`}
+	first := output.PartitionIndex(map[string]interface{}{`ID`: int64(42), `Region`: `East`, `Value`: `a`}, keyFields, 4)
+	second := output.PartitionIndex(map[string]interface{}{`ID`: int64(42), `Region`: `East`, `Value`: `b`}, keyFields, 4)
+	if first != second {
+		t.Fatalf(`expected rows with the same key to share a partition but got %v and %v`, first, second)
+	}
+	used := make(map[int]bool)
+	for id := 0; id < 100; id++ {
+		partition := output.PartitionIndex(map[string]interface{}{`ID`: int64(id), `Region`: `East`}, keyFields, 4)
+		if partition < 0 || partition >= 4 {
+			t.Fatalf(`expected a partition between 0 and 3 but got %v`, partition)
+		}
+		used[partition] = true
+	}
+	if len(used) != 4 {
+		t.Fatalf(`expected rows to be spread across 4 partitions but got %v`, len(used))
+	}
+}
+
+func TestEndpointLocksKeepHubNodesOnOneWriter(t *testing.T) {
+	for _, shared := range []bool{false, true} {
+		for left := 0; left < 3; left++ {
+			for right := 0; right < 3; right++ {
+				locks := output.EndpointLocks(left, right, 3, shared)
+				for otherRight := 0; otherRight < 3; otherRight++ {
+					if !sharesLock(locks, output.EndpointLocks(left, otherRight, 3, shared)) {
+						t.Fatalf(`expected cells with start partition %v to share a lock`, left)
+					}
+				}
+				for otherLeft := 0; otherLeft < 3; otherLeft++ {
+					if !sharesLock(locks, output.EndpointLocks(otherLeft, right, 3, shared)) {
+						t.Fatalf(`expected cells with end partition %v to share a lock`, right)
+					}
+				}
+			}
+		}
+	}
+	if sharesLock(output.EndpointLocks(0, 1, 3, false), output.EndpointLocks(1, 2, 3, false)) {
+		t.Fatalf(`expected cells with different partitions to run together`)
+	}
+	if !sharesLock(output.EndpointLocks(0, 1, 3, true), output.EndpointLocks(1, 2, 3, true)) {
+		t.Fatalf(`expected a node that is the end of one cell and the start of another to share a lock`)
+	}
+}
+
+func sharesLock(first []int, second []int) bool {
+	for _, lock := range first {
+		for _, other := range second {
+			if lock == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestCheckpointStateMergesRecordRanges(t *testing.T) {
+	state := output.CheckpointState{}
+	state.Add([]output.RecordRange{{Start: 20, End: 30}})
+	state.Add([]output.RecordRange{{Start: 0, End: 10}, {Start: 35, End: 40}})
+	if state.Records != 10 || len(state.Ranges) != 2 {
+		t.Fatalf(`expected 10 records and 2 ranges but got %v`, state)
+	}
+	state.Add([]output.RecordRange{{Start: 30, End: 35}})
+	if state.Records != 10 || !reflect.DeepEqual(state.Ranges, []output.RecordRange{{Start: 20, End: 40}}) {
+		t.Fatalf(`expected adjacent ranges to merge but got %v`, state)
+	}
+	state.Add([]output.RecordRange{{Start: 10, End: 20}})
+	if state.Records != 40 || len(state.Ranges) != 0 {
+		t.Fatalf(`expected every record before 40 to be written but got %v`, state)
+	}
+	state.Add([]output.RecordRange{{Start: 45, End: 46}, {Start: 48, End: 50}})
+	for record, expected := range map[int]bool{0: true, 39: true, 40: false, 45: true, 46: false, 49: true, 50: false} {
+		if state.Contains(record) != expected {
+			t.Fatalf(`expected Contains(%v) to be %v`, record, expected)
+		}
+	}
+	if written := state.Written(); written != 43 {
+		t.Fatalf(`expected 43 written records but got %v`, written)
+	}
+	if end := state.End(); end != 50 {
+		t.Fatalf(`expected the checkpoint to end at 50 but got %v`, end)
+	}
+}
+
+func TestAppendRecordBuildsRanges(t *testing.T) {
+	var ranges []output.RecordRange
+	for _, record := range []int{3, 4, 5, 9, 10} {
+		ranges = output.AppendRecord(ranges, record)
+	}
+	expected := []output.RecordRange{{Start: 3, End: 6}, {Start: 9, End: 11}}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf(`expected %v but got %v`, expected, ranges)
+	}
+}
+
+func TestCheckpointSavesRangesAfterAGap(t *testing.T) {
+	store := &output.FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)}
+	checkpoint, err := output.NewCheckpoint(store, `hash`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if checkpoint.State.Written() != 0 {
+		t.Fatalf(`expected a new checkpoint to be empty but got %v`, checkpoint.State)
+	}
+	for _, batch := range [][]output.RecordRange{{{Start: 2, End: 4}}, {{Start: 0, End: 2}}, {{Start: 6, End: 8}}} {
+		err = checkpoint.Commit(batch, false)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+	}
+	err = checkpoint.Commit([]output.RecordRange{{Start: 10, End: 12}}, true)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	reloaded, err := output.NewCheckpoint(store, `hash`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	expected := output.CheckpointState{Records: 4, Ranges: []output.RecordRange{{Start: 6, End: 8}}}
+	if !reflect.DeepEqual(reloaded.State, expected) {
+		t.Fatalf(`expected the saved checkpoint to be %v but got %v`, expected, reloaded.State)
+	}
+
+	changed, err := output.NewCheckpoint(store, `other hash`)
+	if !errors.Is(err, output.ErrCheckpointConfigChanged) {
+		t.Fatalf(`expected ErrCheckpointConfigChanged but got %v`, err)
+	}
+	if changed.State.Written() != 0 {
+		t.Fatalf(`expected a changed config to start empty but got %v`, changed.State)
+	}
+
+	err = checkpoint.Clear()
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	cleared, err := output.NewCheckpoint(store, `hash`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if cleared.State.Written() != 0 {
+		t.Fatalf(`expected a cleared checkpoint to start empty but got %v`, cleared.State)
+	}
+}
+
+func TestRerunAfterSplitBatchDoesNotDuplicateRows(t *testing.T) {
+	store := &output.FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)}
+	written := make(map[int]int)
+	input := make([]map[string]interface{}, 8)
+	for index := range input {
+		input[index] = map[string]interface{}{`ID`: int64(index), output.CheckpointRecordField: index}
+	}
+	deadlock := &neo4j.Neo4jError{Code: `Neo.TransientError.Transaction.DeadlockDetected`, Msg: `deadlock`}
+	constraint := &neo4j.Neo4jError{Code: `Neo.ClientError.Schema.ConstraintValidationFailed`, Msg: `already exists`}
+
+	load := func(failing int) {
+		checkpoint, err := output.NewCheckpoint(store, `hash`)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		var rows []map[string]interface{}
+		for index, row := range input {
+			if !checkpoint.State.Contains(index) {
+				rows = append(rows, row)
+			}
+		}
+		policy := util.RetryPolicy{MaxAttempts: 3, BackoffMilliseconds: 1, SplitAfterDeadlocks: 1}
+		policy.Run(context.Background(), rows, func(rows []map[string]interface{}) error {
+			if len(rows) > 2 {
+				return deadlock
+			}
+			for _, row := range rows {
+				if row[`ID`] == int64(failing) {
+					return constraint
+				}
+			}
+			for _, row := range rows {
+				written[row[output.CheckpointRecordField].(int)]++
+			}
+			return checkpoint.Commit(output.RecordRanges(rows), false)
+		})
+	}
+
+	load(5)
+	if len(written) != 6 {
+		t.Fatalf(`expected 6 rows to be written before the failure but got %v`, len(written))
+	}
+	load(-1)
+	var records []int
+	for record, count := range written {
+		if count != 1 {
+			t.Fatalf(`expected record %v to be written once but it was written %v times`, record, count)
+		}
+		records = append(records, record)
+	}
+	sort.Ints(records)
+	if !reflect.DeepEqual(records, []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf(`expected every record to be written but got %v`, records)
+	}
+}
+
+func TestConfigHashIgnoresCredentials(t *testing.T) {
+	config := output.Configuration{ConnStr: `bolt://localhost:7687`, Username: `one`, Password: `one`, BatchSize: 100, NodeLabel: `TestLabel`}
+	original, err := output.ConfigHash(config)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	config.Username, config.Password, config.Writers = `two`, `two`, 4
+	sameLoad, _ := output.ConfigHash(config)
+	if sameLoad != original {
+		t.Fatalf(`expected credentials and writers not to change the hash`)
+	}
+	config.BatchSize = 200
+	differentLoad, _ := output.ConfigHash(config)
+	if differentLoad == original {
+		t.Fatalf(`expected a different batch size to change the hash`)
+	}
+}
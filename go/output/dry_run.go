@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"github.com/tlarsendataguy/graphyx/util"
+)
+
+func (o *Neo4jOutput) explainBatch(rows []map[string]interface{}) {
+	o.dryRunRecords += len(rows)
+	if o.explained == nil {
+		o.explained = make(map[string]bool)
+	}
+
+	groups, err := o.groupRowsByLabel(rows)
+	if err != nil {
+		o.error(err.Error())
+		return
+	}
+	if o.unmatchedQuery != `` {
+		groups = append([]*labelGroup{{query: o.unmatchedQuery, rows: rows}}, groups...)
+	}
+
+	for _, group := range groups {
+		if o.explained[group.query] {
+			continue
+		}
+		o.explained[group.query] = true
+		explained, err := util.Explain(o.ctx, o.session, group.query, map[string]interface{}{`batch`: group.rows})
+		if err != nil {
+			o.error(fmt.Sprintf("dry run failed for query:\n%v\n%v", group.query, err.Error()))
+			return
+		}
+		explained.Report(o.provider.Io())
+	}
+}
+
+func (o *Neo4jOutput) reportDryRun() {
+	o.provider.Io().Info(fmt.Sprintf(`Dry run complete: %v records were checked and nothing was written`, o.dryRunRecords))
+}
@@ -0,0 +1,270 @@
+package output
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"strings"
+)
+
+type IndexTarget struct {
+	Label      string
+	Properties []string
+}
+
+type NodeIndex struct {
+	Name       string
+	Type       string
+	Label      string
+	Properties []string
+}
+
+func (t IndexTarget) String() string {
+	return fmt.Sprintf(`:%v(%v)`, t.Label, strings.Join(t.Properties, `, `))
+}
+
+func (t IndexTarget) CoveredBy(index NodeIndex) bool {
+	if index.Type != `RANGE` && index.Type != `BTREE` {
+		return false
+	}
+	return t.contains(index)
+}
+
+func (t IndexTarget) UniqueBy(constraint NodeIndex) bool {
+	switch constraint.Type {
+	case `UNIQUENESS`, `NODE_KEY`, `NODE_PROPERTY_UNIQUENESS`:
+		return t.contains(constraint)
+	}
+	return false
+}
+
+func (t IndexTarget) contains(index NodeIndex) bool {
+	if index.Label != t.Label || len(index.Properties) == 0 {
+		return false
+	}
+	for _, property := range index.Properties {
+		if !containsString(t.Properties, property) {
+			return false
+		}
+	}
+	return true
+}
+
+func CreateIndexQuery(target IndexTarget) string {
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (n:`%v`) ON (%v)", escapeName(target.Label), indexProperties(target))
+}
+
+func CreateConstraintQuery(target IndexTarget) string {
+	properties := indexProperties(target)
+	if len(target.Properties) > 1 {
+		properties = `(` + properties + `)`
+	}
+	return fmt.Sprintf("CREATE CONSTRAINT IF NOT EXISTS FOR (n:`%v`) REQUIRE %v IS UNIQUE", escapeName(target.Label), properties)
+}
+
+func indexProperties(target IndexTarget) string {
+	properties := make([]string, len(target.Properties))
+	for index, property := range target.Properties {
+		properties[index] = fmt.Sprintf("n.`%v`", escapeName(property))
+	}
+	return strings.Join(properties, `, `)
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Neo4jOutput) indexTargets() []IndexTarget {
+	var targets []IndexTarget
+	addTarget := func(label string, properties []string) {
+		if label == `` || len(properties) == 0 {
+			return
+		}
+		target := IndexTarget{Label: label, Properties: properties}
+		for _, existing := range targets {
+			if existing.String() == target.String() {
+				return
+			}
+		}
+		targets = append(targets, target)
+	}
+	if o.nodeConfig != nil {
+		properties := make([]string, len(o.nodeConfig.IdFields))
+		for index, field := range o.nodeConfig.IdFields {
+			properties[index] = propertyName(o.nodeConfig.PropertyNames, field)
+		}
+		addTarget(o.nodeConfig.Label, properties)
+	}
+	if o.relConfig != nil {
+		addTarget(o.relConfig.LeftLabel, o.relConfig.LeftNeo4jFields)
+		addTarget(o.relConfig.RightLabel, o.relConfig.RightNeo4jFields)
+	}
+	return targets
+}
+
+func (o *Neo4jOutput) checkIndexes() error {
+	mode := o.config.IndexCheck
+	switch mode {
+	case ``:
+		return nil
+	case `Warn`, `Fail`, `CreateIndex`, `CreateConstraint`:
+	default:
+		return fmt.Errorf(`the index check '%v' is not valid, expected 'Warn', 'Fail', 'CreateIndex', or 'CreateConstraint'`, mode)
+	}
+	if o.config.DryRun && mode != `Fail` {
+		mode = `Warn`
+	}
+
+	targets := o.indexTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	indexes, err := o.nodeIndexes()
+	if err != nil {
+		return fmt.Errorf(`error checking indexes: %v`, err.Error())
+	}
+	created := false
+	for _, target := range targets {
+		if targetIsIndexed(target, indexes) {
+			continue
+		}
+		var query string
+		switch mode {
+		case `Warn`:
+			o.provider.Io().Warn(fmt.Sprintf(`no index or uniqueness constraint was found for %v; each row will scan every node with the label`, target))
+			continue
+		case `Fail`:
+			return fmt.Errorf(`no index or uniqueness constraint was found for %v`, target)
+		case `CreateIndex`:
+			query = CreateIndexQuery(target)
+		case `CreateConstraint`:
+			query = CreateConstraintQuery(target)
+		}
+		err = o.runSchemaQuery(query)
+		if err != nil {
+			return fmt.Errorf("error running\n%v\n%v", query, err.Error())
+		}
+		o.provider.Io().Info(fmt.Sprintf("created missing schema for %v:\n%v", target, query))
+		created = true
+	}
+	if created {
+		return o.runSchemaQuery(`CALL db.awaitIndexes(300)`)
+	}
+	return nil
+}
+
+func (o *Neo4jOutput) mergeTargets() []IndexTarget {
+	if o.nodeConfig != nil {
+		switch o.nodeConfig.WriteMode {
+		case ``, `Merge`, `InsertOnly`:
+			return o.indexTargets()
+		}
+	}
+	if o.relConfig != nil && o.relConfig.MergeEndpoints {
+		return o.indexTargets()
+	}
+	return nil
+}
+
+func (o *Neo4jOutput) checkParallelMerge() error {
+	if o.config.Writers <= 1 || o.config.PartitionByKey || o.config.DryRun {
+		return nil
+	}
+	targets := o.mergeTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	constraints, err := o.nodeSchema(`SHOW CONSTRAINTS YIELD name, type, entityType, labelsOrTypes, properties WHERE entityType = 'NODE' RETURN name, type, labelsOrTypes, properties`)
+	if err != nil {
+		return fmt.Errorf(`error checking uniqueness constraints: %v`, err.Error())
+	}
+	for _, target := range targets {
+		if !targetIsUnique(target, constraints) {
+			return fmt.Errorf(`parallel writers can merge the same %v node at the same time and create duplicates because no uniqueness constraint was found for %v; set PartitionByKey to true, or create the constraint, for example with the 'CreateConstraint' index check`, target.Label, target)
+		}
+	}
+	return nil
+}
+
+func targetIsUnique(target IndexTarget, constraints []NodeIndex) bool {
+	for _, constraint := range constraints {
+		if target.UniqueBy(constraint) {
+			return true
+		}
+	}
+	return false
+}
+
+func targetIsIndexed(target IndexTarget, indexes []NodeIndex) bool {
+	for _, index := range indexes {
+		if target.CoveredBy(index) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Neo4jOutput) nodeIndexes() ([]NodeIndex, error) {
+	return o.nodeSchema(`SHOW INDEXES YIELD name, type, entityType, labelsOrTypes, properties WHERE entityType = 'NODE' RETURN name, type, labelsOrTypes, properties`)
+}
+
+func (o *Neo4jOutput) nodeSchema(query string) ([]NodeIndex, error) {
+	indexes, err := o.session.ExecuteRead(o.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(o.ctx, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var indexes []NodeIndex
+		for result.Next(o.ctx) {
+			record := result.Record()
+			name, _ := record.Get(`name`)
+			indexType, _ := record.Get(`type`)
+			labels, _ := record.Get(`labelsOrTypes`)
+			properties, _ := record.Get(`properties`)
+			labelList := toStrings(labels)
+			if len(labelList) != 1 {
+				continue
+			}
+			indexes = append(indexes, NodeIndex{
+				Name:       fmt.Sprint(name),
+				Type:       fmt.Sprint(indexType),
+				Label:      labelList[0],
+				Properties: toStrings(properties),
+			})
+		}
+		return indexes, result.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return indexes.([]NodeIndex), nil
+}
+
+func (o *Neo4jOutput) runSchemaQuery(query string) error {
+	_, err := o.session.ExecuteWrite(o.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(o.ctx, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(o.ctx)
+	})
+	return err
+}
+
+func toStrings(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"fmt"
+)
+
+type labelGroup struct {
+	query string
+	rows  []map[string]interface{}
+}
+
+func (o *Neo4jOutput) groupRowsByLabel(rows []map[string]interface{}) ([]*labelGroup, error) {
+	if o.labelField == `` {
+		return []*labelGroup{{query: o.query, rows: rows}}, nil
+	}
+	var groups []*labelGroup
+	byLabel := make(map[string]*labelGroup)
+	for _, row := range rows {
+		label, _ := row[o.labelField].(string)
+		group, ok := byLabel[label]
+		if !ok {
+			query, err := o.labelQuery(label)
+			if err != nil {
+				return nil, err
+			}
+			group = &labelGroup{query: query}
+			byLabel[label] = group
+			groups = append(groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+	return groups, nil
+}
+
+func (o *Neo4jOutput) labelQuery(label string) (string, error) {
+	if label == `` {
+		if o.query == `` {
+			return ``, fmt.Errorf(`field %v is blank and no relationship type was provided`, o.labelField)
+		}
+		return o.query, nil
+	}
+	o.labelLock.Lock()
+	defer o.labelLock.Unlock()
+	if query, ok := o.labelQueries[label]; ok {
+		return query, nil
+	}
+
+	var query string
+	var err error
+	if o.nodeConfig != nil {
+		config := *o.nodeConfig
+		config.ExtraLabels = concatFields(config.ExtraLabels, SplitLabels(label))
+		query, err = NodeQuery(&config)
+	} else {
+		config := *o.relConfig
+		config.Label = label
+		query, err = RelationshipQuery(&config)
+	}
+	if err != nil {
+		return ``, err
+	}
+	o.labelQueries[label] = query
+	return query, nil
+}
@@ -1,47 +1,102 @@
 package output
 
 import (
+	"context"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"github.com/tlarsendataguy/graphyx/util"
+	"sync"
+	"time"
 )
 
 type Configuration struct {
-	ConnStr        string
-	Username       string
-	Password       string
-	Database       string
-	ExportObject   string
-	BatchSize      int
-	NodeLabel      string
-	NodeIdFields   []string
-	NodePropFields []string
-	RelLabel       string
-	RelIdFields    []string
-	RelPropFields  []string
-	RelLeftLabel   string
-	RelLeftFields  []map[string]interface{}
-	RelRightLabel  string
-	RelRightFields []map[string]interface{}
+	ConnStr                             string
+	Username                            string
+	Password                            string
+	Database                            string
+	ExportObject                        string
+	BatchSize                           int
+	NodeLabel                           string
+	NodeIdFields                        []interface{}
+	NodePropFields                      []interface{}
+	RelLabel                            string
+	RelIdFields                         []interface{}
+	RelPropFields                       []interface{}
+	RelLeftLabel                        string
+	RelLeftFields                       []map[string]interface{}
+	RelRightLabel                       string
+	RelRightFields                      []map[string]interface{}
+	BisectErrors                        bool
+	RelEndpointMode                     string
+	WriteMode                           string
+	NodeCreatePropFields                []string
+	NodeMatchPropFields                 []string
+	RelCreatePropFields                 []string
+	RelMatchPropFields                  []string
+	NodeExtraLabels                     []string
+	NodeLabelField                      string
+	RelTypeField                        string
+	CredentialProvider                  string
+	CredentialFile                      string
+	CredentialPrecedence                string
+	AuthType                            string
+	Realm                               string
+	TlsCaFile                           string
+	TlsSkipVerify                       bool
+	MaxConnectionPoolSize               int
+	MaxTransactionRetrySeconds          int
+	ConnectionAcquisitionTimeoutSeconds int
+	UserAgent                           string
+	DurationFields                      []string
+	DateTimeZone                        string
+	DateTimeType                        string
+	ListFields                          []util.ListOptions
+	SpatialFormat                       string
+	DecimalFields                       []util.DecimalOptions
+	DryRun                              bool
+	IndexCheck                          string
+	Writers                             int
+	PartitionByKey                      bool
+	RetryPolicy                         util.RetryPolicy
+	SortByEndpoints                     bool
+	Checkpoint                          string
+	CheckpointFile                      string
 }
 
 type Neo4jOutput struct {
 	query            string
+	unmatchedQuery   string
+	nodeConfig       *NodeConfig
+	relConfig        *RelationshipConfig
+	labelField       string
+	labelQueries     map[string]string
+	labelLock        sync.Mutex
 	config           Configuration
 	provider         sdk.Provider
 	copier           []util.CopyData
 	outputFields     []string
 	batch            []map[string]interface{}
 	currentBatchSize int
-	driver           neo4j.Driver
-	session          neo4j.Session
+	ctx              context.Context
+	driver           neo4j.DriverWithContext
+	session          neo4j.SessionWithContext
 	doExport         bool
+	errors           *recordOutput
+	unmatched        *recordOutput
+	summary          *summaryOutput
+	batchNumber      int
+	writers          *writerPipeline
+	checkpoint       *Checkpoint
+	recordIndex      int
+	explained        map[string]bool
+	dryRunRecords    int
 }
 
 func (o *Neo4jOutput) Init(provider sdk.Provider) {
 	var err error
 	o.provider = provider
+	o.ctx = context.Background()
 	o.config, err = decodeConfig(provider.ToolConfig())
 	if err != nil {
 		provider.Io().Error(err.Error())
@@ -51,23 +106,23 @@ func (o *Neo4jOutput) Init(provider sdk.Provider) {
 	o.batch = make([]map[string]interface{}, o.config.BatchSize)
 	if o.config.ExportObject == `Node` {
 		o.generateNodeQuery()
-		o.outputFields = append(o.config.NodeIdFields, o.config.NodePropFields...)
+		if o.nodeConfig != nil {
+			o.outputFields = concatFields(o.nodeConfig.IdFields, o.nodeConfig.PropFields, o.nodeConfig.CreatePropFields, o.nodeConfig.MatchPropFields)
+		}
 	}
 	if o.config.ExportObject == `Relationship` {
 		o.generateRelationshipQuery()
-		for _, field := range o.config.RelLeftFields {
-			for ayxField := range field {
-				o.outputFields = append(o.outputFields, ayxField)
-			}
-		}
-		for _, field := range o.config.RelRightFields {
-			for ayxField := range field {
-				o.outputFields = append(o.outputFields, ayxField)
-			}
+		if o.relConfig != nil {
+			o.outputFields = concatFields(o.relConfig.LeftAlteryxFields, o.relConfig.RightAlteryxFields, o.relConfig.PropFields, o.relConfig.IdFields, o.relConfig.CreatePropFields, o.relConfig.MatchPropFields)
 		}
-		o.outputFields = append(o.outputFields, o.config.RelPropFields...)
-		o.outputFields = append(o.outputFields, o.config.RelIdFields...)
 	}
+	if o.labelField != `` {
+		o.outputFields = append(o.outputFields, o.labelField)
+		o.labelQueries = make(map[string]string)
+	}
+	o.errors = newRecordOutput(provider.GetOutputAnchor(`Errors`), o.outputFields, `Neo4j Error Code`, `Neo4j Error Message`)
+	o.unmatched = newRecordOutput(provider.GetOutputAnchor(`Unmatched`), o.outputFields, `Missing Endpoint`)
+	o.summary = newSummaryOutput(provider.GetOutputAnchor(`Summary`))
 	outputFieldLen := len(o.outputFields)
 	for index := range o.batch {
 		o.batch[index] = make(map[string]interface{}, outputFieldLen)
@@ -79,33 +134,85 @@ func (o *Neo4jOutput) Init(provider sdk.Provider) {
 }
 
 func (o *Neo4jOutput) OnInputConnectionOpened(connection sdk.InputConnection) {
+	if o.errors != nil {
+		o.errors.open()
+		o.unmatched.open()
+		o.summary.open()
+	}
 	if !o.doExport {
 		return
 	}
 
 	var err error
 
+	durationFields := make(map[string]bool, len(o.config.DurationFields))
+	for _, field := range o.config.DurationFields {
+		durationFields[field] = true
+	}
+
+	listFields := make(map[string]util.ListOptions, len(o.config.ListFields))
+	for _, options := range o.config.ListFields {
+		listFields[options.Field] = options
+	}
+
+	decimalFields := make(map[string]util.DecimalOptions, len(o.config.DecimalFields))
+	for _, options := range o.config.DecimalFields {
+		decimalFields[options.Field] = options
+	}
+
 	var copier util.CopyData
 	incomingInfo := connection.Metadata()
 	for _, field := range o.outputFields {
-		copier, err = util.FindFieldAndGenerateCopier(field, incomingInfo)
+		if options, ok := decimalFields[field]; ok {
+			copier, err = util.DecimalCopier(options, incomingInfo)
+		} else {
+			copier, err = util.FindFieldAndGenerateCopierWithOptions(field, incomingInfo, util.CopierOptions{SpatialFormat: o.config.SpatialFormat})
+		}
+		if err != nil {
+			o.error(err.Error())
+			return
+		}
+		if durationFields[field] {
+			copier = util.DurationCopier(field, copier)
+		}
+		copier, err = util.DateTimeCopier(field, copier, util.DateTimeOptions{Zone: o.config.DateTimeZone, Type: o.config.DateTimeType})
 		if err != nil {
-			o.error(fmt.Sprintf(`field %v was not contained in the record`, field))
+			o.error(err.Error())
 			return
 		}
+		if options, ok := listFields[field]; ok {
+			copier, err = util.ListCopier(options, copier)
+			if err != nil {
+				o.error(err.Error())
+				return
+			}
+		}
 		o.copier = append(o.copier, copier)
 	}
 
-	username, password := util.GetCredentials(o.config.ConnStr, o.config.Username, o.config.Password, o.provider)
-	o.driver, err = neo4j.NewDriver(o.config.ConnStr, neo4j.BasicAuth(username, password, ""))
+	o.driver, err = util.AcquireDriver(o.ctx, o.connectionConfig(), o.provider)
+	if err != nil {
+		o.error(err.Error())
+		return
+	}
+	o.session = o.driver.NewSession(o.ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: o.config.Database})
+	err = o.checkIndexes()
+	if err != nil {
+		o.error(err.Error())
+		return
+	}
+	err = o.checkParallelMerge()
 	if err != nil {
 		o.error(err.Error())
 		return
 	}
-	o.session = o.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: o.config.Database})
-	err = o.driver.VerifyConnectivity()
+	err = o.openCheckpoint()
 	if err != nil {
 		o.error(err.Error())
+		return
+	}
+	if o.config.Writers > 1 && !o.config.DryRun {
+		o.writers = newWriterPipeline(o)
 	}
 }
 
@@ -116,8 +223,21 @@ func (o *Neo4jOutput) OnRecordPacket(connection sdk.InputConnection) {
 
 	packet := connection.Read()
 	for packet.Next() {
+		if o.skipRecord() {
+			continue
+		}
+		if o.writers != nil {
+			o.writers.copyRecord(packet.Record())
+			if !o.doExport {
+				return
+			}
+			continue
+		}
 		if o.currentBatchSize >= o.config.BatchSize {
 			o.sendBatch()
+			if !o.doExport {
+				return
+			}
 		}
 		copyFrom := packet.Record()
 		copyTo := o.batch[o.currentBatchSize]
@@ -127,31 +247,152 @@ func (o *Neo4jOutput) OnRecordPacket(connection sdk.InputConnection) {
 				o.provider.Io().Error(err.Error())
 			}
 		}
+		if o.checkpoint != nil {
+			copyTo[CheckpointRecordField] = o.recordIndex - 1
+		}
 		o.currentBatchSize++
 	}
 	o.provider.Io().UpdateProgress(connection.Progress())
 }
 
 func (o *Neo4jOutput) sendBatch() {
-	_, err := o.session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		return tx.Run(o.query, map[string]interface{}{`batch`: o.batch[:o.currentBatchSize]})
+	if o.config.DryRun {
+		o.explainBatch(o.batch[:o.currentBatchSize])
+		o.currentBatchSize = 0
+		return
+	}
+	o.batchNumber++
+	result := o.writeBatch(o.ctx, o.session, o.batchNumber, o.batch[:o.currentBatchSize], o.errors.isConnected())
+	o.applyResult(result)
+	o.currentBatchSize = 0
+}
+
+func (o *Neo4jOutput) writeBatch(ctx context.Context, session neo4j.SessionWithContext, number int, rows []map[string]interface{}, reportFailures bool) *batchResult {
+	start := time.Now()
+	result := &batchResult{number: number, counters: loadCounters{records: len(rows)}}
+	if o.config.SortByEndpoints && o.relConfig != nil {
+		util.SortRowsByFields(rows, concatFields(o.relConfig.LeftAlteryxFields, o.relConfig.RightAlteryxFields))
+	}
+	failures := o.config.RetryPolicy.Run(ctx, rows, func(rows []map[string]interface{}) error {
+		return o.writeRows(ctx, session, rows, result)
+	})
+	for _, failure := range failures {
+		if !reportFailures {
+			result.err = failure.Err
+			return result
+		}
+		o.handleFailedRows(ctx, session, failure.Rows, failure.Err, result)
+	}
+	result.counters.elapsed = time.Since(start)
+	return result
+}
+
+func (o *Neo4jOutput) writeRows(ctx context.Context, session neo4j.SessionWithContext, rows []map[string]interface{}, result *batchResult) error {
+	groups, err := o.groupRowsByLabel(rows)
+	if err != nil {
+		return err
+	}
+	var unmatched []unmatchedEndpoint
+	var counters loadCounters
+	store, ranges := o.transactionalCheckpoint(rows)
+	_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		var txErr error
+		counters = loadCounters{}
+		if o.unmatchedQuery != `` {
+			unmatched, txErr = findUnmatchedEndpoints(ctx, tx, o.unmatchedQuery, rows)
+			if txErr != nil {
+				return nil, txErr
+			}
+		}
+		for _, group := range groups {
+			cursor, txErr := tx.Run(ctx, group.query, map[string]interface{}{`batch`: group.rows})
+			if txErr != nil {
+				return nil, txErr
+			}
+			summary, txErr := cursor.Consume(ctx)
+			if txErr != nil {
+				return nil, txErr
+			}
+			counters.addSummary(summary)
+		}
+		if store != nil {
+			return nil, store.commitInTransaction(ctx, tx, o.checkpoint.hash, ranges)
+		}
+		return nil, nil
 	})
 	if err != nil {
-		o.error(err.Error())
+		return err
+	}
+	result.committed = append(result.committed, ranges...)
+	result.checkpointed = store != nil
+	result.counters.addCounters(counters)
+	result.counters.unmatchedRecords += len(unmatched)
+	for _, endpoint := range unmatched {
+		result.unmatched = append(result.unmatched, unmatchedRow{row: rows[endpoint.index], missing: endpoint.missing})
+	}
+	return nil
+}
+
+func (o *Neo4jOutput) handleFailedRows(ctx context.Context, session neo4j.SessionWithContext, rows []map[string]interface{}, err error, result *batchResult) {
+	if !o.config.BisectErrors || len(rows) == 1 {
+		code, message := util.Neo4jErrorDetails(err)
+		result.failed = append(result.failed, failedRows{rows: rows, code: code, message: message})
+		result.counters.failedRecords += len(rows)
 		return
 	}
-	o.currentBatchSize = 0
+	middle := len(rows) / 2
+	for _, half := range [][]map[string]interface{}{rows[:middle], rows[middle:]} {
+		halfErr := o.writeRows(ctx, session, half, result)
+		if halfErr != nil {
+			o.handleFailedRows(ctx, session, half, halfErr, result)
+		}
+	}
+}
+
+func (o *Neo4jOutput) applyResult(result *batchResult) {
+	if result.err != nil {
+		o.commitCheckpoint(result.committed, result.checkpointed)
+		o.error(result.err.Error())
+		return
+	}
+	for _, unmatched := range result.unmatched {
+		o.unmatched.writeRow(unmatched.row, unmatched.missing)
+	}
+	var reported []RecordRange
+	for _, failed := range result.failed {
+		o.errors.write(failed.rows, failed.code, failed.message)
+		if o.checkpoint != nil {
+			reported = append(reported, RecordRanges(failed.rows)...)
+		}
+	}
+	o.summary.writeBatch(result.number, result.counters)
+	o.commitCheckpoint(result.committed, result.checkpointed)
+	if len(reported) > 0 {
+		o.commitCheckpoint(reported, false)
+	}
 }
 
 func (o *Neo4jOutput) OnComplete() {
-	if o.currentBatchSize > 0 && o.doExport {
+	if o.writers != nil {
+		o.writers.finish()
+	} else if o.currentBatchSize > 0 && o.doExport {
 		o.sendBatch()
 	}
+	o.closeCheckpoint()
 	if o.session != nil {
-		_ = o.session.Close()
+		_ = o.session.Close(o.ctx)
 	}
 	if o.driver != nil {
-		_ = o.driver.Close()
+		_ = util.ReleaseDriver(o.ctx, o.driver)
+	}
+	if o.batchNumber > 0 {
+		o.summary.writeTotal()
+	}
+	if o.config.DryRun && o.doExport {
+		o.reportDryRun()
+	}
+	if failed := o.summary.total.failedRecords; failed > 0 {
+		o.provider.Io().Warn(fmt.Sprintf(`%v records failed to export and were sent to the Errors anchor`, failed))
 	}
 	o.provider.Io().UpdateProgress(1.0)
 }
@@ -170,12 +411,30 @@ func (o *Neo4jOutput) Query() string {
 }
 
 func (o *Neo4jOutput) generateNodeQuery() {
-	var err error
+	names := make(map[string]string)
+	idFields, err := mapPropertyFields(o.config.NodeIdFields, names)
+	if err != nil {
+		o.provider.Io().Error(err.Error())
+		return
+	}
+	propFields, err := mapPropertyFields(o.config.NodePropFields, names)
+	if err != nil {
+		o.provider.Io().Error(err.Error())
+		return
+	}
+
 	nodeConfig := &NodeConfig{
-		Label:      o.config.NodeLabel,
-		IdFields:   o.config.NodeIdFields,
-		PropFields: o.config.NodePropFields,
+		Label:            o.config.NodeLabel,
+		IdFields:         idFields,
+		PropFields:       propFields,
+		CreatePropFields: o.config.NodeCreatePropFields,
+		MatchPropFields:  o.config.NodeMatchPropFields,
+		ExtraLabels:      o.config.NodeExtraLabels,
+		PropertyNames:    names,
+		WriteMode:        o.config.WriteMode,
 	}
+	o.nodeConfig = nodeConfig
+	o.labelField = o.config.NodeLabelField
 	o.query, err = NodeQuery(nodeConfig)
 	if err != nil {
 		o.provider.Io().Error(err.Error())
@@ -200,6 +459,17 @@ func (o *Neo4jOutput) generateRelationshipQuery() {
 		o.provider.Io().Error(err.Error())
 		return
 	}
+	names := make(map[string]string)
+	propFields, err := mapPropertyFields(o.config.RelPropFields, names)
+	if err != nil {
+		o.provider.Io().Error(err.Error())
+		return
+	}
+	idFields, err := mapPropertyFields(o.config.RelIdFields, names)
+	if err != nil {
+		o.provider.Io().Error(err.Error())
+		return
+	}
 
 	relConfig := &RelationshipConfig{
 		LeftLabel:          o.config.RelLeftLabel,
@@ -209,8 +479,35 @@ func (o *Neo4jOutput) generateRelationshipQuery() {
 		RightAlteryxFields: rightAlteryxFields,
 		RightNeo4jFields:   rightNeo4jFields,
 		Label:              o.config.RelLabel,
-		PropFields:         o.config.RelPropFields,
-		IdFields:           o.config.RelIdFields,
+		PropFields:         propFields,
+		IdFields:           idFields,
+		CreatePropFields:   o.config.RelCreatePropFields,
+		MatchPropFields:    o.config.RelMatchPropFields,
+		PropertyNames:      names,
+		WriteMode:          o.config.WriteMode,
+	}
+	o.relConfig = relConfig
+	switch o.config.RelEndpointMode {
+	case ``, `Match`:
+	case `Report`:
+		o.unmatchedQuery, err = UnmatchedEndpointsQuery(relConfig)
+		if err != nil {
+			o.provider.Io().Error(err.Error())
+			return
+		}
+	case `Merge`:
+		relConfig.MergeEndpoints = true
+	default:
+		o.provider.Io().Error(fmt.Sprintf(`the RelEndpointMode property '%v' is not valid, expected 'Match', 'Report', or 'Merge'`, o.config.RelEndpointMode))
+		return
+	}
+	o.labelField = o.config.RelTypeField
+	if relConfig.Label == `` && o.labelField != `` {
+		err = validateEndpoints(relConfig)
+		if err != nil {
+			o.provider.Io().Error(err.Error())
+		}
+		return
 	}
 	o.query, err = RelationshipQuery(relConfig)
 	if err != nil {
@@ -247,3 +544,46 @@ func fieldsToAyxAndNeo4jLists(fields []map[string]interface{}) ([]string, []stri
 	}
 	return alteryxFields, neo4jFields, nil
 }
+
+func mapPropertyFields(fields []interface{}, names map[string]string) ([]string, error) {
+	var alteryxFields []string
+	for _, field := range fields {
+		switch f := field.(type) {
+		case string:
+			alteryxFields = append(alteryxFields, f)
+		case map[string]interface{}:
+			mappedFields, neo4jFields, err := fieldsToAyxAndNeo4jLists([]map[string]interface{}{f})
+			if err != nil {
+				return nil, err
+			}
+			for index, alteryxField := range mappedFields {
+				names[alteryxField] = neo4jFields[index]
+			}
+			alteryxFields = append(alteryxFields, mappedFields...)
+		default:
+			return nil, fmt.Errorf(`the property field '%v' is not a field name or a field mapping; the tool configuration is not formatted properly`, field)
+		}
+	}
+	return alteryxFields, nil
+}
+
+func (o *Neo4jOutput) connectionConfig() util.ConnectionConfig {
+	return util.ConnectionConfig{
+		Credentials: util.CredentialSource{
+			Url:        o.config.ConnStr,
+			Username:   o.config.Username,
+			Password:   o.config.Password,
+			Provider:   o.config.CredentialProvider,
+			Precedence: o.config.CredentialPrecedence,
+			File:       o.config.CredentialFile,
+			AuthType:   o.config.AuthType,
+			Realm:      o.config.Realm,
+		},
+		TlsCaFile:                           o.config.TlsCaFile,
+		TlsSkipVerify:                       o.config.TlsSkipVerify,
+		MaxConnectionPoolSize:               o.config.MaxConnectionPoolSize,
+		MaxTransactionRetrySeconds:          o.config.MaxTransactionRetrySeconds,
+		ConnectionAcquisitionTimeoutSeconds: o.config.ConnectionAcquisitionTimeoutSeconds,
+		UserAgent:                           o.config.UserAgent,
+	}
+}
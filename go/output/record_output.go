@@ -0,0 +1,88 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"time"
+)
+
+const source = `Neo4j Output`
+
+type recordOutput struct {
+	anchor      sdk.OutputAnchor
+	info        *sdk.OutgoingRecordInfo
+	batchFields []string
+	outFields   []string
+	extraFields []string
+}
+
+func newRecordOutput(anchor sdk.OutputAnchor, batchFields []string, extraFields ...string) *recordOutput {
+	editor := &sdk.EditingRecordInfo{}
+	outFields := make([]string, len(batchFields))
+	for index, field := range batchFields {
+		outFields[index] = editor.AddV_WStringField(field, source, 1073741823)
+	}
+	extraOutFields := make([]string, len(extraFields))
+	for index, field := range extraFields {
+		extraOutFields[index] = editor.AddV_WStringField(field, source, 1073741823)
+	}
+	return &recordOutput{
+		anchor:      anchor,
+		info:        editor.GenerateOutgoingRecordInfo(),
+		batchFields: batchFields,
+		outFields:   outFields,
+		extraFields: extraOutFields,
+	}
+}
+
+func (r *recordOutput) open() {
+	r.anchor.Open(r.info)
+}
+
+func (r *recordOutput) isConnected() bool {
+	return r.anchor.NumConnections() > 0
+}
+
+func (r *recordOutput) write(rows []map[string]interface{}, extraValues ...string) {
+	for _, row := range rows {
+		r.writeRow(row, extraValues...)
+	}
+}
+
+func (r *recordOutput) writeRow(row map[string]interface{}, extraValues ...string) {
+	for index, field := range r.batchFields {
+		value, isNull := formatRecordValue(row[field])
+		if isNull {
+			r.info.StringFields[r.outFields[index]].SetNull()
+			continue
+		}
+		r.info.StringFields[r.outFields[index]].SetString(value)
+	}
+	for index, field := range r.extraFields {
+		r.info.StringFields[field].SetString(extraValues[index])
+	}
+	r.anchor.Write()
+}
+
+func formatRecordValue(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case nil:
+		return ``, true
+	case string:
+		return typed, false
+	case time.Time:
+		return typed.Format(`2006-01-02 15:04:05`), false
+	case dbtype.Date:
+		return typed.Time().Format(`2006-01-02`), false
+	case []interface{}:
+		bytes, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprintf(`%v`, typed), false
+		}
+		return string(bytes), false
+	default:
+		return fmt.Sprintf(`%v`, typed), false
+	}
+}
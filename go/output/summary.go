@@ -0,0 +1,99 @@
+package output
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"time"
+)
+
+type loadCounters struct {
+	records              int
+	failedRecords        int
+	unmatchedRecords     int
+	nodesCreated         int
+	relationshipsCreated int
+	propertiesSet        int
+	labelsAdded          int
+	elapsed              time.Duration
+}
+
+func (c *loadCounters) addSummary(summary neo4j.ResultSummary) {
+	counters := summary.Counters()
+	c.nodesCreated += counters.NodesCreated()
+	c.relationshipsCreated += counters.RelationshipsCreated()
+	c.propertiesSet += counters.PropertiesSet()
+	c.labelsAdded += counters.LabelsAdded()
+}
+
+func (c *loadCounters) addCounters(other loadCounters) {
+	c.records += other.records
+	c.failedRecords += other.failedRecords
+	c.unmatchedRecords += other.unmatchedRecords
+	c.nodesCreated += other.nodesCreated
+	c.relationshipsCreated += other.relationshipsCreated
+	c.propertiesSet += other.propertiesSet
+	c.labelsAdded += other.labelsAdded
+	c.elapsed += other.elapsed
+}
+
+type summaryOutput struct {
+	anchor               sdk.OutputAnchor
+	info                 *sdk.OutgoingRecordInfo
+	summaryType          string
+	batch                string
+	records              string
+	failedRecords        string
+	unmatchedRecords     string
+	nodesCreated         string
+	relationshipsCreated string
+	propertiesSet        string
+	labelsAdded          string
+	elapsed              string
+	total                loadCounters
+}
+
+func newSummaryOutput(anchor sdk.OutputAnchor) *summaryOutput {
+	editor := &sdk.EditingRecordInfo{}
+	output := &summaryOutput{anchor: anchor}
+	output.summaryType = editor.AddV_WStringField(`Summary Type`, source, 10)
+	output.batch = editor.AddInt64Field(`Batch`, source)
+	output.records = editor.AddInt64Field(`Records`, source)
+	output.failedRecords = editor.AddInt64Field(`Failed Records`, source)
+	output.unmatchedRecords = editor.AddInt64Field(`Unmatched Records`, source)
+	output.nodesCreated = editor.AddInt64Field(`Nodes Created`, source)
+	output.relationshipsCreated = editor.AddInt64Field(`Relationships Created`, source)
+	output.propertiesSet = editor.AddInt64Field(`Properties Set`, source)
+	output.labelsAdded = editor.AddInt64Field(`Labels Added`, source)
+	output.elapsed = editor.AddDoubleField(`Elapsed Seconds`, source)
+	output.info = editor.GenerateOutgoingRecordInfo()
+	return output
+}
+
+func (s *summaryOutput) open() {
+	s.anchor.Open(s.info)
+}
+
+func (s *summaryOutput) writeBatch(batch int, counters loadCounters) {
+	s.total.addCounters(counters)
+	s.info.StringFields[s.summaryType].SetString(`Batch`)
+	s.info.IntFields[s.batch].SetInt(batch)
+	s.write(counters)
+}
+
+func (s *summaryOutput) writeTotal() {
+	s.info.StringFields[s.summaryType].SetString(`Total`)
+	s.info.IntFields[s.batch].SetNull()
+	s.write(s.total)
+}
+
+func (s *summaryOutput) write(counters loadCounters) {
+	s.info.IntFields[s.records].SetInt(counters.records)
+	s.info.IntFields[s.failedRecords].SetInt(counters.failedRecords)
+	s.info.IntFields[s.unmatchedRecords].SetInt(counters.unmatchedRecords)
+	s.info.IntFields[s.nodesCreated].SetInt(counters.nodesCreated)
+	s.info.IntFields[s.relationshipsCreated].SetInt(counters.relationshipsCreated)
+	s.info.IntFields[s.propertiesSet].SetInt(counters.propertiesSet)
+	s.info.IntFields[s.labelsAdded].SetInt(counters.labelsAdded)
+	s.info.FloatFields[s.elapsed].SetFloat(counters.elapsed.Seconds())
+	s.anchor.Write()
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"context"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type unmatchedEndpoint struct {
+	index   int
+	missing string
+}
+
+func findUnmatchedEndpoints(ctx context.Context, tx neo4j.ManagedTransaction, query string, rows []map[string]interface{}) ([]unmatchedEndpoint, error) {
+	result, err := tx.Run(ctx, query, map[string]interface{}{`batch`: rows})
+	if err != nil {
+		return nil, err
+	}
+	var unmatched []unmatchedEndpoint
+	for result.Next(ctx) {
+		record := result.Record()
+		index, _ := record.Get(`index`)
+		leftMissing, _ := record.Get(`leftMissing`)
+		rightMissing, _ := record.Get(`rightMissing`)
+		missing := `Both`
+		if leftMissing == false {
+			missing = `Right`
+		}
+		if rightMissing == false {
+			missing = `Left`
+		}
+		unmatched = append(unmatched, unmatchedEndpoint{index: int(index.(int64)), missing: missing})
+	}
+	return unmatched, result.Err()
+}
@@ -0,0 +1,280 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"hash/fnv"
+	"sync"
+)
+
+type batchResult struct {
+	number       int
+	counters     loadCounters
+	unmatched    []unmatchedRow
+	failed       []failedRows
+	buffer       *batchBuffer
+	committed    []RecordRange
+	checkpointed bool
+	err          error
+}
+
+type unmatchedRow struct {
+	row     map[string]interface{}
+	missing string
+}
+
+type failedRows struct {
+	rows    []map[string]interface{}
+	code    string
+	message string
+}
+
+type batchBuffer struct {
+	number int
+	rows   []map[string]interface{}
+	size   int
+	locks  []int
+}
+
+// writerPipeline hands full batches to a pool of writers, each with its own session. Batches are only
+// reused after their result has been applied on the engine thread, so the SDK's output anchors are
+// never touched by the writers.
+//
+// With PartitionByKey, rows are grouped into cells by a hash of their keys. Each cell locks the key
+// partitions it writes to, and a batch is only handed to a writer once no running batch holds any of
+// its locks. Relationships lock a partition for each endpoint, so hub nodes on either side are never
+// written by two writers at once.
+type writerPipeline struct {
+	output          *Neo4jOutput
+	ctx             context.Context
+	cancel          context.CancelFunc
+	sessions        []neo4j.SessionWithContext
+	jobs            chan *batchBuffer
+	results         chan *batchResult
+	current         []*batchBuffer
+	free            []*batchBuffer
+	scratch         map[string]interface{}
+	leftFields      []string
+	rightFields     []string
+	partitions      int
+	sharedEndpoints bool
+	locked          map[int]bool
+	inFlight        int
+	reportFailures  bool
+	done            sync.WaitGroup
+}
+
+func newWriterPipeline(o *Neo4jOutput) *writerPipeline {
+	writers := o.config.Writers
+	ctx, cancel := context.WithCancel(o.ctx)
+	w := &writerPipeline{
+		output:         o,
+		ctx:            ctx,
+		cancel:         cancel,
+		locked:         make(map[int]bool),
+		reportFailures: o.errors.isConnected(),
+	}
+	cells := 1
+	if o.config.PartitionByKey {
+		w.leftFields, w.rightFields = o.partitionFields()
+	}
+	if len(w.leftFields) > 0 {
+		w.partitions = writers
+		cells = writers
+	}
+	if len(w.rightFields) > 0 {
+		cells = writers * writers
+		w.sharedEndpoints = o.relConfig.LeftLabel == o.relConfig.RightLabel
+	}
+	buffers := cells + writers
+	if buffers < writers*2 {
+		buffers = writers * 2
+	}
+	for index := 0; index < buffers; index++ {
+		w.free = append(w.free, &batchBuffer{rows: make([]map[string]interface{}, o.config.BatchSize)})
+	}
+	w.jobs = make(chan *batchBuffer, buffers)
+	w.results = make(chan *batchResult, buffers)
+	for cell := 0; cell < cells; cell++ {
+		w.current = append(w.current, w.nextBuffer(cell))
+	}
+	for index := 0; index < writers; index++ {
+		session := o.driver.NewSession(o.ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: o.config.Database})
+		w.sessions = append(w.sessions, session)
+		w.done.Add(1)
+		go w.write(session)
+	}
+	return w
+}
+
+func (o *Neo4jOutput) partitionFields() ([]string, []string) {
+	if o.nodeConfig != nil {
+		return o.nodeConfig.IdFields, nil
+	}
+	if o.relConfig != nil {
+		return o.relConfig.LeftAlteryxFields, o.relConfig.RightAlteryxFields
+	}
+	return nil, nil
+}
+
+func PartitionIndex(row map[string]interface{}, keyFields []string, partitions int) int {
+	hash := fnv.New32a()
+	for _, field := range keyFields {
+		_, _ = fmt.Fprint(hash, row[field])
+		_, _ = hash.Write([]byte{0})
+	}
+	return int(hash.Sum32() % uint32(partitions))
+}
+
+// EndpointLocks lists the partition locks held by a batch of relationships whose start nodes hash to
+// the left partition and whose end nodes hash to the right partition. When both endpoints have the same
+// label a node can be either endpoint, so both sides share one set of locks.
+func EndpointLocks(left int, right int, partitions int, sharedEndpoints bool) []int {
+	if !sharedEndpoints {
+		right += partitions
+	}
+	if left == right {
+		return []int{left}
+	}
+	return []int{left, right}
+}
+
+func (w *writerPipeline) cell(row map[string]interface{}) int {
+	if len(w.current) == 1 {
+		return 0
+	}
+	left := PartitionIndex(row, w.leftFields, w.partitions)
+	if len(w.rightFields) == 0 {
+		return left
+	}
+	return left*w.partitions + PartitionIndex(row, w.rightFields, w.partitions)
+}
+
+func (w *writerPipeline) cellLocks(cell int) []int {
+	if w.partitions == 0 {
+		return nil
+	}
+	if len(w.rightFields) == 0 {
+		return []int{cell}
+	}
+	return EndpointLocks(cell/w.partitions, cell%w.partitions, w.partitions, w.sharedEndpoints)
+}
+
+func (w *writerPipeline) write(session neo4j.SessionWithContext) {
+	defer w.done.Done()
+	for buffer := range w.jobs {
+		result := w.output.writeBatch(w.ctx, session, buffer.number, buffer.rows[:buffer.size], w.reportFailures)
+		result.buffer = buffer
+		w.results <- result
+	}
+}
+
+func (w *writerPipeline) copyRecord(record sdk.Record) {
+	o := w.output
+	if w.scratch == nil {
+		w.scratch = make(map[string]interface{}, len(o.outputFields))
+	}
+	for _, copyData := range o.copier {
+		err := copyData(record, w.scratch)
+		if err != nil {
+			o.provider.Io().Error(err.Error())
+		}
+	}
+	if o.checkpoint != nil {
+		w.scratch[CheckpointRecordField] = o.recordIndex - 1
+	}
+	cell := w.cell(w.scratch)
+	buffer := w.current[cell]
+	buffer.rows[buffer.size], w.scratch = w.scratch, buffer.rows[buffer.size]
+	buffer.size++
+	if buffer.size < len(buffer.rows) {
+		return
+	}
+	w.send(cell)
+	w.applyReady()
+	w.current[cell] = w.nextBuffer(cell)
+}
+
+func (w *writerPipeline) send(cell int) {
+	o := w.output
+	buffer := w.current[cell]
+	for w.isLocked(buffer.locks) {
+		w.apply(<-w.results)
+	}
+	for _, lock := range buffer.locks {
+		w.locked[lock] = true
+	}
+	o.batchNumber++
+	buffer.number = o.batchNumber
+	w.inFlight++
+	w.jobs <- buffer
+}
+
+func (w *writerPipeline) isLocked(locks []int) bool {
+	for _, lock := range locks {
+		if w.locked[lock] {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *writerPipeline) nextBuffer(cell int) *batchBuffer {
+	for len(w.free) == 0 {
+		w.apply(<-w.results)
+	}
+	buffer := w.free[len(w.free)-1]
+	w.free = w.free[:len(w.free)-1]
+	buffer.size = 0
+	buffer.locks = w.cellLocks(cell)
+	return buffer
+}
+
+func (w *writerPipeline) applyReady() {
+	for {
+		select {
+		case result := <-w.results:
+			w.apply(result)
+		default:
+			return
+		}
+	}
+}
+
+func (w *writerPipeline) apply(result *batchResult) {
+	w.inFlight--
+	for _, lock := range result.buffer.locks {
+		delete(w.locked, lock)
+	}
+	if w.output.doExport {
+		w.output.applyResult(result)
+		if !w.output.doExport {
+			w.cancel()
+		}
+	} else {
+		// The load has already failed, but batches that were in flight may still have committed.
+		w.output.commitCheckpoint(result.committed, result.checkpointed)
+	}
+	w.free = append(w.free, result.buffer)
+}
+
+func (w *writerPipeline) finish() {
+	if w.output.doExport {
+		for cell, buffer := range w.current {
+			if buffer.size > 0 {
+				w.send(cell)
+			}
+		}
+	}
+	close(w.jobs)
+	for w.inFlight > 0 {
+		w.apply(<-w.results)
+	}
+	w.done.Wait()
+	w.cancel()
+	for _, session := range w.sessions {
+		_ = session.Close(w.output.ctx)
+	}
+}
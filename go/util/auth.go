@@ -0,0 +1,33 @@
+package util
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+)
+
+func GetAuthToken(source CredentialSource, provider sdk.Provider) (neo4j.AuthToken, error) {
+	if source.AuthType == `None` {
+		return neo4j.NoAuth(), nil
+	}
+	username, password, err := GetCredentials(source, provider)
+	if err != nil {
+		return neo4j.AuthToken{}, err
+	}
+	return NewAuthToken(source.AuthType, source.Realm, username, password)
+}
+
+func NewAuthToken(authType string, realm string, username string, password string) (neo4j.AuthToken, error) {
+	switch authType {
+	case ``, `Basic`:
+		return neo4j.BasicAuth(username, password, realm), nil
+	case `Bearer`:
+		return neo4j.BearerAuth(password), nil
+	case `Kerberos`:
+		return neo4j.KerberosAuth(password), nil
+	case `None`:
+		return neo4j.NoAuth(), nil
+	default:
+		return neo4j.AuthToken{}, fmt.Errorf(`the AuthType '%v' is not valid, expected 'Basic', 'Bearer', 'Kerberos', or 'None'`, authType)
+	}
+}
@@ -0,0 +1,75 @@
+package util_test
+
+import (
+	"github.com/tlarsendataguy/graphyx/util"
+	"testing"
+)
+
+func TestBasicAuthTokenWithRealm(t *testing.T) {
+	token, err := util.NewAuthToken(`Basic`, `myrealm`, `user`, `pass`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if scheme := token.Tokens[`scheme`]; scheme != `basic` {
+		t.Fatalf(`expected 'basic' but got '%v'`, scheme)
+	}
+	if realm := token.Tokens[`realm`]; realm != `myrealm` {
+		t.Fatalf(`expected 'myrealm' but got '%v'`, realm)
+	}
+}
+
+func TestDefaultAuthTokenIsBasic(t *testing.T) {
+	token, err := util.NewAuthToken(``, ``, `user`, `pass`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if scheme := token.Tokens[`scheme`]; scheme != `basic` {
+		t.Fatalf(`expected 'basic' but got '%v'`, scheme)
+	}
+	if principal := token.Tokens[`principal`]; principal != `user` {
+		t.Fatalf(`expected 'user' but got '%v'`, principal)
+	}
+}
+
+func TestBearerAuthTokenUsesPassword(t *testing.T) {
+	token, err := util.NewAuthToken(`Bearer`, ``, ``, `sso token`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if scheme := token.Tokens[`scheme`]; scheme != `bearer` {
+		t.Fatalf(`expected 'bearer' but got '%v'`, scheme)
+	}
+	if credentials := token.Tokens[`credentials`]; credentials != `sso token` {
+		t.Fatalf(`expected 'sso token' but got '%v'`, credentials)
+	}
+}
+
+func TestKerberosAuthTokenUsesPassword(t *testing.T) {
+	token, err := util.NewAuthToken(`Kerberos`, ``, ``, `dGlja2V0`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if scheme := token.Tokens[`scheme`]; scheme != `kerberos` {
+		t.Fatalf(`expected 'kerberos' but got '%v'`, scheme)
+	}
+	if ticket := token.Tokens[`credentials`]; ticket != `dGlja2V0` {
+		t.Fatalf(`expected 'dGlja2V0' but got '%v'`, ticket)
+	}
+}
+
+func TestNoAuthToken(t *testing.T) {
+	token, err := util.NewAuthToken(`None`, ``, `user`, `pass`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if scheme := token.Tokens[`scheme`]; scheme != `none` {
+		t.Fatalf(`expected 'none' but got '%v'`, scheme)
+	}
+}
+
+func TestInvalidAuthType(t *testing.T) {
+	_, err := util.NewAuthToken(`Digest`, ``, `user`, `pass`)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"os"
+	"strings"
+	"time"
+)
+
+type ConnectionConfig struct {
+	Credentials                         CredentialSource
+	TlsCaFile                           string
+	TlsSkipVerify                       bool
+	MaxConnectionPoolSize               int
+	MaxTransactionRetrySeconds          int
+	ConnectionAcquisitionTimeoutSeconds int
+	UserAgent                           string
+}
+
+func ConfigureDriver(connection ConnectionConfig) (string, func(*neo4j.Config), error) {
+	url := connection.Credentials.Url
+	if connection.TlsSkipVerify {
+		url = skipVerifyUrl(url)
+	}
+
+	var tlsConfig *tls.Config
+	if connection.TlsCaFile != `` {
+		pem, err := os.ReadFile(connection.TlsCaFile)
+		if err != nil {
+			return ``, nil, fmt.Errorf(`error reading CA file %v: %v`, connection.TlsCaFile, err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return ``, nil, fmt.Errorf(`no certificates were found in CA file %v`, connection.TlsCaFile)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	configurer := func(config *neo4j.Config) {
+		if tlsConfig != nil {
+			config.TlsConfig = tlsConfig
+		}
+		if connection.MaxConnectionPoolSize > 0 {
+			config.MaxConnectionPoolSize = connection.MaxConnectionPoolSize
+		}
+		if connection.MaxTransactionRetrySeconds > 0 {
+			config.MaxTransactionRetryTime = time.Duration(connection.MaxTransactionRetrySeconds) * time.Second
+		}
+		if connection.ConnectionAcquisitionTimeoutSeconds > 0 {
+			config.ConnectionAcquisitionTimeout = time.Duration(connection.ConnectionAcquisitionTimeoutSeconds) * time.Second
+		}
+		if connection.UserAgent != `` {
+			config.UserAgent = connection.UserAgent
+		}
+	}
+	return url, configurer, nil
+}
+
+func skipVerifyUrl(url string) string {
+	for _, scheme := range []string{`bolt+s://`, `neo4j+s://`} {
+		if strings.HasPrefix(url, scheme) {
+			return strings.Replace(url, `+s://`, `+ssc://`, 1)
+		}
+	}
+	return url
+}
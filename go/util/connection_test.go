@@ -0,0 +1,139 @@
+package util_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/graphyx/util"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigureDriverSettings(t *testing.T) {
+	url, configurer, err := util.ConfigureDriver(util.ConnectionConfig{
+		Credentials:                         util.CredentialSource{Url: `neo4j+s://localhost:7687`},
+		MaxConnectionPoolSize:               5,
+		MaxTransactionRetrySeconds:          60,
+		ConnectionAcquisitionTimeoutSeconds: 10,
+		UserAgent:                           `graphyx-test`,
+	})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if url != `neo4j+s://localhost:7687` {
+		t.Fatalf(`expected unchanged url but got '%v'`, url)
+	}
+	config := &neo4j.Config{}
+	configurer(config)
+	if config.MaxConnectionPoolSize != 5 {
+		t.Fatalf(`expected pool size 5 but got %v`, config.MaxConnectionPoolSize)
+	}
+	if config.MaxTransactionRetryTime != 60*time.Second {
+		t.Fatalf(`expected 60s retry time but got %v`, config.MaxTransactionRetryTime)
+	}
+	if config.ConnectionAcquisitionTimeout != 10*time.Second {
+		t.Fatalf(`expected 10s acquisition timeout but got %v`, config.ConnectionAcquisitionTimeout)
+	}
+	if config.UserAgent != `graphyx-test` {
+		t.Fatalf(`expected 'graphyx-test' but got '%v'`, config.UserAgent)
+	}
+	if config.TlsConfig != nil {
+		t.Fatalf(`expected no TLS config but got one`)
+	}
+}
+
+func TestConfigureDriverKeepsDefaults(t *testing.T) {
+	_, configurer, err := util.ConfigureDriver(util.ConnectionConfig{
+		Credentials: util.CredentialSource{Url: `bolt://localhost:7687`},
+	})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	config := &neo4j.Config{MaxConnectionPoolSize: 100, UserAgent: `default`}
+	configurer(config)
+	if config.MaxConnectionPoolSize != 100 || config.UserAgent != `default` {
+		t.Fatalf(`expected defaults to be kept but got %v and '%v'`, config.MaxConnectionPoolSize, config.UserAgent)
+	}
+}
+
+func TestConfigureDriverSkipVerify(t *testing.T) {
+	for url, expected := range map[string]string{
+		`bolt+s://localhost:7687`:  `bolt+ssc://localhost:7687`,
+		`neo4j+s://localhost:7687`: `neo4j+ssc://localhost:7687`,
+		`bolt://localhost:7687`:    `bolt://localhost:7687`,
+	} {
+		actual, _, err := util.ConfigureDriver(util.ConnectionConfig{
+			Credentials:   util.CredentialSource{Url: url},
+			TlsSkipVerify: true,
+		})
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if actual != expected {
+			t.Fatalf(`expected '%v' but got '%v'`, expected, actual)
+		}
+	}
+}
+
+func TestConfigureDriverWithCaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), `ca.pem`)
+	err := os.WriteFile(path, generateTestCa(t), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	_, configurer, err := util.ConfigureDriver(util.ConnectionConfig{
+		Credentials: util.CredentialSource{Url: `bolt+s://localhost:7687`},
+		TlsCaFile:   path,
+	})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	config := &neo4j.Config{}
+	configurer(config)
+	if config.TlsConfig == nil || config.TlsConfig.RootCAs == nil {
+		t.Fatalf(`expected a TLS config with root CAs`)
+	}
+}
+
+func TestConfigureDriverWithInvalidCaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), `ca.pem`)
+	err := os.WriteFile(path, []byte(`not a certificate`), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	_, _, err = util.ConfigureDriver(util.ConnectionConfig{
+		Credentials: util.CredentialSource{Url: `bolt+s://localhost:7687`},
+		TlsCaFile:   path,
+	})
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func generateTestCa(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: `graphyx test CA`},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: der})
+}
@@ -4,13 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 )
 
 type CopyData func(sdk.Record, map[string]interface{}) error
 
+type CopierOptions struct {
+	SpatialFormat string
+}
+
 func FindFieldAndGenerateCopier(field string, incomingInfo sdk.IncomingRecordInfo) (CopyData, error) {
+	return FindFieldAndGenerateCopierWithOptions(field, incomingInfo, CopierOptions{})
+}
+
+func FindFieldAndGenerateCopierWithOptions(field string, incomingInfo sdk.IncomingRecordInfo, options CopierOptions) (CopyData, error) {
 	var copier CopyData
 	for _, incomingField := range incomingInfo.Fields() {
 		if field == incomingField.Name {
@@ -112,8 +120,43 @@ func FindFieldAndGenerateCopier(field string, incomingInfo sdk.IncomingRecordInf
 					return errors.New(fmt.Sprintf(`error: field does not contain a valid JSON list: %v`, err.Error()))
 				}
 				return copier, nil
+			case `SpatialObj`:
+				return spatialCopier(field, incomingInfo, options.SpatialFormat)
+			default:
+				return nil, &UnsupportedFieldTypeError{Field: field, Type: incomingField.Type}
 			}
 		}
 	}
-	return nil, fmt.Errorf(`could not find field '%v' in the record`, field)
+	if incomingInfo.NumFields() == 0 {
+		return nil, fmt.Errorf(`field %v was not contained in the record because the incoming record has no fields`, field)
+	}
+	return nil, fmt.Errorf(`field %v was not contained in the record`, field)
+}
+
+func spatialCopier(field string, incomingInfo sdk.IncomingRecordInfo, format string) (CopyData, error) {
+	var convert func([]byte) (interface{}, error)
+	switch format {
+	case ``, `Point`:
+		convert = func(blob []byte) (interface{}, error) { return SpatialObjToPoint(blob) }
+	case `WKT`:
+		convert = func(blob []byte) (interface{}, error) { return SpatialObjToWKT(blob) }
+	default:
+		return nil, fmt.Errorf(`the spatial format '%v' is not valid, expected 'Point' or 'WKT'`, format)
+	}
+	blobField, _ := incomingInfo.GetBlobField(field)
+	getBlob := blobField.GetValue
+	return func(copyFrom sdk.Record, copyTo map[string]interface{}) error {
+		blob := getBlob(copyFrom)
+		if blob == nil {
+			copyTo[field] = nil
+			return nil
+		}
+		value, err := convert(blob)
+		if err != nil {
+			copyTo[field] = nil
+			return fmt.Errorf(`error: field %v: %v`, field, err.Error())
+		}
+		copyTo[field] = value
+		return nil
+	}, nil
 }
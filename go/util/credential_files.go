@@ -0,0 +1,303 @@
+package util
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+type StoredCredential struct {
+	Username string
+	Password string
+}
+
+type EncryptedFileCredentialProvider struct {
+	Path           string
+	Key            string
+	SkipWithoutKey bool
+}
+
+func (p *EncryptedFileCredentialProvider) GetCredentials(url string) (string, string, error) {
+	if p.Path == `` {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	content, err := os.ReadFile(p.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	if err != nil {
+		return ``, ``, err
+	}
+	key := p.Key
+	if key == `` {
+		key = os.Getenv(`GRAPHYX_CREDENTIAL_KEY`)
+	}
+	if key == `` && p.SkipWithoutKey {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	if key == `` {
+		return ``, ``, fmt.Errorf(`credential file %v is encrypted but GRAPHYX_CREDENTIAL_KEY is not set`, p.Path)
+	}
+	credentials, err := DecryptCredentials(content, key)
+	if err != nil {
+		return ``, ``, fmt.Errorf(`error reading credential file %v: %v`, p.Path, err.Error())
+	}
+	credential, ok := credentials[url]
+	if !ok {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	return credential.Username, credential.Password, nil
+}
+
+func (p *EncryptedFileCredentialProvider) Name() string {
+	return fmt.Sprintf(`credential file %v`, p.Path)
+}
+
+const credentialSaltSize = 16
+const credentialKeyIterations = 600000
+
+func EncryptCredentials(credentials map[string]StoredCredential, key string) ([]byte, error) {
+	plain, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, credentialSaltSize)
+	_, err = io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newCredentialCipher(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(append(salt, nonce...), nonce, plain, nil)
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(encoded, sealed)
+	return encoded, nil
+}
+
+func DecryptCredentials(content []byte, key string) (map[string]StoredCredential, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < credentialSaltSize {
+		return nil, errors.New(`the file is too short to be a credential file`)
+	}
+	salt, sealed := sealed[:credentialSaltSize], sealed[credentialSaltSize:]
+	gcm, err := newCredentialCipher(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New(`the file is too short to be a credential file`)
+	}
+	nonce, encrypted := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, encrypted, nil)
+	if err != nil {
+		return nil, err
+	}
+	var credentials map[string]StoredCredential
+	err = json.Unmarshal(plain, &credentials)
+	return credentials, err
+}
+
+func SaveCredentialToFile(path string, key string, url string, credential StoredCredential) error {
+	credentials := map[string]StoredCredential{}
+	content, err := os.ReadFile(path)
+	if err == nil {
+		credentials, err = DecryptCredentials(content, key)
+		if err != nil {
+			return fmt.Errorf(`error reading credential file %v: %v`, path, err.Error())
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if credentials == nil {
+		credentials = map[string]StoredCredential{}
+	}
+	credentials[url] = credential
+	content, err = EncryptCredentials(credentials, key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
+
+func newCredentialCipher(key string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2Sha256([]byte(key), salt, credentialKeyIterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// PBKDF2 (RFC 8018) with HMAC-SHA256, so the passphrase is stretched without adding a dependency.
+func pbkdf2Sha256(password []byte, salt []byte, iterations int, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLength; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		_ = binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for index := range t {
+				t[index] ^= u[index]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLength]
+}
+
+type NetrcCredentialProvider struct {
+	Path        string
+	SkipDefault bool
+}
+
+func (p *NetrcCredentialProvider) GetCredentials(url string) (string, string, error) {
+	path := p.Path
+	if path == `` {
+		path = defaultNetrcPath()
+	}
+	if path == `` {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	if err != nil {
+		return ``, ``, err
+	}
+	defer file.Close()
+
+	entries, err := parseNetrc(file)
+	if err != nil {
+		return ``, ``, err
+	}
+	host, hostname := ``, url
+	parsed, err := neturl.Parse(url)
+	if err == nil && parsed.Host != `` {
+		host, hostname = parsed.Host, parsed.Hostname()
+	}
+	var fallback *netrcEntry
+	for index, entry := range entries {
+		if entry.machine == `` {
+			if fallback == nil && !p.SkipDefault {
+				fallback = &entries[index]
+			}
+			continue
+		}
+		if entry.machine == host || entry.machine == hostname {
+			return entry.login, entry.password, nil
+		}
+	}
+	if fallback != nil {
+		return fallback.login, fallback.password, nil
+	}
+	return ``, ``, ErrCredentialsNotFound
+}
+
+func (p *NetrcCredentialProvider) Name() string {
+	if p.Path == `` {
+		return `netrc file`
+	}
+	return fmt.Sprintf(`netrc file %v`, p.Path)
+}
+
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+func parseNetrc(reader io.Reader) ([]netrcEntry, error) {
+	var entries []netrcEntry
+	var current *netrcEntry
+	inMacro := false
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			if strings.TrimSpace(line) == `` {
+				inMacro = false
+			}
+			continue
+		}
+		tokens := strings.Fields(line)
+		for index := 0; index < len(tokens); index++ {
+			token := tokens[index]
+			if strings.HasPrefix(token, `#`) {
+				break
+			}
+			switch token {
+			case `default`:
+				entries = append(entries, netrcEntry{})
+				current = &entries[len(entries)-1]
+				continue
+			case `macdef`:
+				inMacro = true
+				index = len(tokens)
+				continue
+			}
+			if index+1 >= len(tokens) {
+				return nil, fmt.Errorf(`netrc keyword '%v' is missing a value`, token)
+			}
+			index++
+			value := tokens[index]
+			switch token {
+			case `machine`:
+				entries = append(entries, netrcEntry{machine: value})
+				current = &entries[len(entries)-1]
+			case `login`:
+				if current != nil {
+					current.login = value
+				}
+			case `password`:
+				if current != nil {
+					current.password = value
+				}
+			}
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func defaultNetrcPath() string {
+	if path := os.Getenv(`NETRC`); path != `` {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ``
+	}
+	if runtime.GOOS == `windows` {
+		return filepath.Join(home, `_netrc`)
+	}
+	return filepath.Join(home, `.netrc`)
+}
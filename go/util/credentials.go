@@ -3,24 +3,189 @@ package util
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"github.com/danieljoos/wincred"
 	"github.com/tlarsendataguy/goalteryx/sdk"
 	"golang.org/x/text/encoding/unicode"
+	"os"
 	"strings"
 )
 
-func GetCredentials(url, username, password string, provider sdk.Provider) (string, string) {
-	creds, err := wincred.GetGenericCredential(url)
-	if err == nil && creds != nil {
-		builder := strings.Builder{}
-		decoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
-		scanner := bufio.NewScanner(decoder.Reader(bytes.NewReader(creds.CredentialBlob)))
-		for scanner.Scan() {
-			builder.Write(scanner.Bytes())
+var ErrCredentialsNotFound = errors.New(`no credentials were found`)
+
+type CredentialProvider interface {
+	GetCredentials(url string) (string, string, error)
+	Name() string
+}
+
+type CredentialSource struct {
+	Url        string
+	Username   string
+	Password   string
+	Provider   string
+	Precedence string
+	File       string
+	AuthType   string
+	Realm      string
+}
+
+func GetCredentials(source CredentialSource, provider sdk.Provider) (string, string, error) {
+	credentialProvider, err := NewCredentialProvider(source, provider.Io().DecryptPassword)
+	if err != nil {
+		return ``, ``, err
+	}
+	username, password, used, err := ResolveCredentials(credentialProvider, source.Url)
+	if err != nil {
+		return ``, ``, err
+	}
+	provider.Io().Info(fmt.Sprintf(`using Neo4j credentials from %v`, used.Name()))
+	return username, password, nil
+}
+
+func ResolveCredentials(provider CredentialProvider, url string) (string, string, CredentialProvider, error) {
+	if chain, ok := provider.(CredentialChain); ok {
+		return chain.resolve(url)
+	}
+	username, password, err := provider.GetCredentials(url)
+	return username, password, provider, err
+}
+
+func NewCredentialProvider(source CredentialSource, decrypt func(string) string) (CredentialProvider, error) {
+	toolConfig := &ToolConfigCredentialProvider{Username: source.Username, Password: source.Password, Decrypt: decrypt}
+	switch source.Provider {
+	case ``, `Auto`:
+		encryptedFile := source.File
+		if encryptedFile == `` {
+			encryptedFile = os.Getenv(`GRAPHYX_CREDENTIAL_FILE`)
 		}
+		fallbacks := CredentialChain{
+			&EnvironmentCredentialProvider{UrlKeyedOnly: true},
+			&EncryptedFileCredentialProvider{Path: encryptedFile, SkipWithoutKey: true},
+			&NetrcCredentialProvider{SkipDefault: true},
+		}
+		hasToolConfig := source.Username != `` || source.Password != ``
+		switch source.Precedence {
+		case ``, `WindowsFirst`:
+			chain := CredentialChain{&WindowsCredentialProvider{}}
+			if hasToolConfig {
+				return append(append(chain, toolConfig), fallbacks...), nil
+			}
+			return append(append(chain, fallbacks...), toolConfig), nil
+		case `ToolConfigFirst`:
+			chain := append(CredentialChain{&WindowsCredentialProvider{}}, fallbacks...)
+			if hasToolConfig {
+				return append(CredentialChain{toolConfig}, chain...), nil
+			}
+			return append(chain, toolConfig), nil
+		default:
+			return nil, fmt.Errorf(`the credential precedence '%v' is not valid, expected 'WindowsFirst' or 'ToolConfigFirst'`, source.Precedence)
+		}
+	case `WindowsCredentialManager`:
+		return &WindowsCredentialProvider{}, nil
+	case `Environment`:
+		return &EnvironmentCredentialProvider{}, nil
+	case `EncryptedFile`:
+		return &EncryptedFileCredentialProvider{Path: source.File}, nil
+	case `Netrc`:
+		return &NetrcCredentialProvider{Path: source.File}, nil
+	case `ToolConfig`:
+		return toolConfig, nil
+	default:
+		return nil, fmt.Errorf(`the credential provider '%v' is not valid, expected 'Auto', 'WindowsCredentialManager', 'Environment', 'EncryptedFile', 'Netrc', or 'ToolConfig'`, source.Provider)
+	}
+}
+
+type CredentialChain []CredentialProvider
+
+func (c CredentialChain) GetCredentials(url string) (string, string, error) {
+	username, password, _, err := c.resolve(url)
+	return username, password, err
+}
+
+func (c CredentialChain) Name() string {
+	return `Auto`
+}
+
+func (c CredentialChain) resolve(url string) (string, string, CredentialProvider, error) {
+	for _, provider := range c {
+		username, password, err := provider.GetCredentials(url)
+		if errors.Is(err, ErrCredentialsNotFound) {
+			continue
+		}
+		return username, password, provider, err
+	}
+	return ``, ``, c, ErrCredentialsNotFound
+}
+
+type ToolConfigCredentialProvider struct {
+	Username string
+	Password string
+	Decrypt  func(string) string
+}
 
-		return creds.UserName, builder.String()
+func (p *ToolConfigCredentialProvider) GetCredentials(_ string) (string, string, error) {
+	if p.Decrypt == nil {
+		return p.Username, p.Password, nil
 	}
+	return p.Username, p.Decrypt(p.Password), nil
+}
+
+func (p *ToolConfigCredentialProvider) Name() string {
+	return `the tool configuration`
+}
+
+type WindowsCredentialProvider struct{}
 
-	return username, provider.Io().DecryptPassword(password)
+func (p *WindowsCredentialProvider) GetCredentials(url string) (string, string, error) {
+	creds, err := wincred.GetGenericCredential(url)
+	if err != nil || creds == nil {
+		return ``, ``, ErrCredentialsNotFound
+	}
+	builder := strings.Builder{}
+	decoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	scanner := bufio.NewScanner(decoder.Reader(bytes.NewReader(creds.CredentialBlob)))
+	for scanner.Scan() {
+		builder.Write(scanner.Bytes())
+	}
+	return creds.UserName, builder.String(), nil
+}
+
+func (p *WindowsCredentialProvider) Name() string {
+	return `Windows Credential Manager`
+}
+
+type EnvironmentCredentialProvider struct {
+	UrlKeyedOnly bool
+}
+
+func (p *EnvironmentCredentialProvider) GetCredentials(url string) (string, string, error) {
+	keys := []string{`_` + EnvironmentKey(url)}
+	if !p.UrlKeyedOnly {
+		keys = append(keys, ``)
+	}
+	for _, key := range keys {
+		username, ok := os.LookupEnv(`GRAPHYX_NEO4J_USER` + key)
+		if !ok {
+			continue
+		}
+		return username, os.Getenv(`GRAPHYX_NEO4J_PASSWORD` + key), nil
+	}
+	return ``, ``, ErrCredentialsNotFound
+}
+
+func (p *EnvironmentCredentialProvider) Name() string {
+	return `environment variables`
+}
+
+func EnvironmentKey(url string) string {
+	builder := strings.Builder{}
+	for _, char := range strings.ToUpper(url) {
+		if (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') {
+			builder.WriteRune(char)
+			continue
+		}
+		builder.WriteRune('_')
+	}
+	return builder.String()
 }
@@ -0,0 +1,302 @@
+package util_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"github.com/tlarsendataguy/graphyx/util"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testUrl = `bolt://localhost:7687`
+
+func TestEnvironmentKey(t *testing.T) {
+	key := util.EnvironmentKey(`neo4j+s://my-db.example.com:7687`)
+	if expected := `NEO4J_S___MY_DB_EXAMPLE_COM_7687`; key != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, key)
+	}
+}
+
+func TestEnvironmentCredentialsKeyedByUrl(t *testing.T) {
+	t.Setenv(`GRAPHYX_NEO4J_USER`, `default user`)
+	t.Setenv(`GRAPHYX_NEO4J_PASSWORD`, `default password`)
+	t.Setenv(`GRAPHYX_NEO4J_USER_BOLT___LOCALHOST_7687`, `keyed user`)
+	t.Setenv(`GRAPHYX_NEO4J_PASSWORD_BOLT___LOCALHOST_7687`, `keyed password`)
+
+	provider := &util.EnvironmentCredentialProvider{}
+	username, password, err := provider.GetCredentials(testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `keyed user` || password != `keyed password` {
+		t.Fatalf(`expected keyed credentials but got '%v' and '%v'`, username, password)
+	}
+
+	username, password, err = provider.GetCredentials(`bolt://otherhost:7687`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `default user` || password != `default password` {
+		t.Fatalf(`expected default credentials but got '%v' and '%v'`, username, password)
+	}
+}
+
+func TestEnvironmentCredentialsNotFound(t *testing.T) {
+	provider := &util.EnvironmentCredentialProvider{}
+	_, _, err := provider.GetCredentials(`bolt://nowhere:7687`)
+	if !errors.Is(err, util.ErrCredentialsNotFound) {
+		t.Fatalf(`expected ErrCredentialsNotFound but got %v`, err)
+	}
+}
+
+func TestEncryptedFileCredentials(t *testing.T) {
+	content, err := util.EncryptCredentials(map[string]util.StoredCredential{
+		testUrl: {Username: `file user`, Password: `file password`},
+	}, `secret`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	path := filepath.Join(t.TempDir(), `credentials`)
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	provider := &util.EncryptedFileCredentialProvider{Path: path, Key: `secret`}
+	username, password, err := provider.GetCredentials(testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `file user` || password != `file password` {
+		t.Fatalf(`expected file credentials but got '%v' and '%v'`, username, password)
+	}
+
+	_, _, err = provider.GetCredentials(`bolt://otherhost:7687`)
+	if !errors.Is(err, util.ErrCredentialsNotFound) {
+		t.Fatalf(`expected ErrCredentialsNotFound but got %v`, err)
+	}
+
+	provider.Key = `wrong`
+	_, _, err = provider.GetCredentials(testUrl)
+	if err == nil || errors.Is(err, util.ErrCredentialsNotFound) {
+		t.Fatalf(`expected a decryption error but got %v`, err)
+	}
+}
+
+func TestEncryptedCredentialFilesAreSalted(t *testing.T) {
+	credentials := map[string]util.StoredCredential{testUrl: {Username: `file user`, Password: `file password`}}
+	first, err := util.EncryptCredentials(credentials, `secret`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	second, err := util.EncryptCredentials(credentials, `secret`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	firstBytes, _ := base64.StdEncoding.DecodeString(string(first))
+	secondBytes, _ := base64.StdEncoding.DecodeString(string(second))
+	if bytes.Equal(firstBytes[:16], secondBytes[:16]) {
+		t.Fatalf(`expected each file to have its own salt`)
+	}
+	for _, content := range [][]byte{first, second} {
+		decrypted, err := util.DecryptCredentials(content, `secret`)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if decrypted[testUrl].Password != `file password` {
+			t.Fatalf(`expected 'file password' but got '%v'`, decrypted[testUrl].Password)
+		}
+	}
+}
+
+func TestSaveCredentialToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), `credentials`)
+	err := util.SaveCredentialToFile(path, `secret`, testUrl, util.StoredCredential{Username: `first`, Password: `first password`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = util.SaveCredentialToFile(path, `secret`, `bolt://otherhost:7687`, util.StoredCredential{Username: `other`, Password: `other password`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	err = util.SaveCredentialToFile(path, `wrong`, testUrl, util.StoredCredential{Username: `lost`, Password: `lost`})
+	if err == nil {
+		t.Fatalf(`expected an error saving with the wrong key but got none`)
+	}
+
+	provider := &util.EncryptedFileCredentialProvider{Path: path, Key: `secret`}
+	for url, expected := range map[string]string{testUrl: `first`, `bolt://otherhost:7687`: `other`} {
+		username, _, err := provider.GetCredentials(url)
+		if err != nil {
+			t.Fatalf(`expected no error but got: %v`, err.Error())
+		}
+		if username != expected {
+			t.Fatalf(`expected '%v' but got '%v'`, expected, username)
+		}
+	}
+}
+
+func TestNetrcCredentials(t *testing.T) {
+	netrc := `# test file
+machine otherhost login other password otherpass
+machine localhost:7687
+  login netrc
+  password netrcpass
+macdef init
+cd /somewhere
+
+default login anonymous password guest
+`
+	path := filepath.Join(t.TempDir(), `.netrc`)
+	err := os.WriteFile(path, []byte(netrc), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	provider := &util.NetrcCredentialProvider{Path: path}
+	username, password, err := provider.GetCredentials(testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `netrc` || password != `netrcpass` {
+		t.Fatalf(`expected netrc credentials but got '%v' and '%v'`, username, password)
+	}
+
+	username, password, err = provider.GetCredentials(`neo4j://otherhost:7687`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `other` || password != `otherpass` {
+		t.Fatalf(`expected other credentials but got '%v' and '%v'`, username, password)
+	}
+
+	username, password, err = provider.GetCredentials(`bolt://unknown:7687`)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `anonymous` || password != `guest` {
+		t.Fatalf(`expected default credentials but got '%v' and '%v'`, username, password)
+	}
+}
+
+func TestCredentialChainPrecedence(t *testing.T) {
+	t.Setenv(`GRAPHYX_NEO4J_USER_BOLT___LOCALHOST_7687`, `env user`)
+	t.Setenv(`GRAPHYX_NEO4J_PASSWORD_BOLT___LOCALHOST_7687`, `env password`)
+	t.Setenv(`NETRC`, filepath.Join(t.TempDir(), `missing`))
+
+	provider, err := util.NewCredentialProvider(util.CredentialSource{
+		Url:        testUrl,
+		Username:   `config user`,
+		Password:   `config password`,
+		Precedence: `ToolConfigFirst`,
+	}, nil)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	username, _, used, err := util.ResolveCredentials(provider, testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `config user` || used.Name() != `the tool configuration` {
+		t.Fatalf(`expected 'config user' from the tool configuration but got '%v' from %v`, username, used.Name())
+	}
+
+	provider, err = util.NewCredentialProvider(util.CredentialSource{Url: testUrl}, nil)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	username, _, used, err = util.ResolveCredentials(provider, testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `env user` || used.Name() != `environment variables` {
+		t.Fatalf(`expected 'env user' from environment variables but got '%v' from %v`, username, used.Name())
+	}
+}
+
+func TestAutoCredentialsTryWindowsFirst(t *testing.T) {
+	provider, err := util.NewCredentialProvider(util.CredentialSource{Url: testUrl, Username: `config user`, Password: `config password`}, nil)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	chain, ok := provider.(util.CredentialChain)
+	if !ok || len(chain) < 2 {
+		t.Fatalf(`expected a credential chain but got %T`, provider)
+	}
+	if _, ok := chain[0].(*util.WindowsCredentialProvider); !ok {
+		t.Fatalf(`expected Windows credentials first but got %v`, chain[0].Name())
+	}
+	if _, ok := chain[1].(*util.ToolConfigCredentialProvider); !ok {
+		t.Fatalf(`expected the tool configuration second but got %v`, chain[1].Name())
+	}
+
+	provider, err = util.NewCredentialProvider(util.CredentialSource{Url: testUrl, Username: `config user`, Precedence: `ToolConfigFirst`}, nil)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if _, ok := provider.(util.CredentialChain)[0].(*util.ToolConfigCredentialProvider); !ok {
+		t.Fatalf(`expected the tool configuration first with ToolConfigFirst`)
+	}
+
+	_, err = util.NewCredentialProvider(util.CredentialSource{Url: testUrl, Precedence: `Random`}, nil)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestAutoCredentialsSkipFallbacks(t *testing.T) {
+	t.Setenv(`GRAPHYX_NEO4J_USER`, `default user`)
+	t.Setenv(`GRAPHYX_NEO4J_PASSWORD`, `default password`)
+	t.Setenv(`GRAPHYX_CREDENTIAL_KEY`, ``)
+	netrc := filepath.Join(t.TempDir(), `.netrc`)
+	err := os.WriteFile(netrc, []byte("default login anonymous password guest\n"), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	t.Setenv(`NETRC`, netrc)
+	encrypted := filepath.Join(t.TempDir(), `credentials`)
+	err = os.WriteFile(encrypted, []byte(`not checked without a key`), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+
+	provider, err := util.NewCredentialProvider(util.CredentialSource{Url: testUrl, File: encrypted}, nil)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	username, _, used, err := util.ResolveCredentials(provider, testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if username != `` || used.Name() != `the tool configuration` {
+		t.Fatalf(`expected the empty tool configuration to be used but got '%v' from %v`, username, used.Name())
+	}
+}
+
+func TestToolConfigCredentialsAreDecrypted(t *testing.T) {
+	provider, err := util.NewCredentialProvider(util.CredentialSource{
+		Url:      testUrl,
+		Username: `config user`,
+		Password: `encrypted`,
+		Provider: `ToolConfig`,
+	}, func(value string) string { return `decrypted ` + value })
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	_, password, err := provider.GetCredentials(testUrl)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if password != `decrypted encrypted` {
+		t.Fatalf(`expected 'decrypted encrypted' but got '%v'`, password)
+	}
+}
+
+func TestInvalidCredentialProvider(t *testing.T) {
+	_, err := util.NewCredentialProvider(util.CredentialSource{Provider: `Invalid`}, nil)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
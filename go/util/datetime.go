@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"time"
+	_ "time/tzdata"
+)
+
+type DateTimeOptions struct {
+	Zone string
+	Type string
+}
+
+func LoadTimeZone(name string) (*time.Location, error) {
+	switch name {
+	case ``:
+		return nil, nil
+	case `UTC`:
+		return time.UTC, nil
+	case `Local`:
+		return time.Local, nil
+	default:
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf(`'%v' is not a valid time zone, expected 'UTC', 'Local', or an IANA zone name such as 'America/Chicago'`, name)
+		}
+		return location, nil
+	}
+}
+
+func DateTimeCopier(field string, copier CopyData, options DateTimeOptions) (CopyData, error) {
+	location, err := LoadTimeZone(options.Zone)
+	if err != nil {
+		return nil, err
+	}
+	var convert func(time.Time) interface{}
+	switch options.Type {
+	case ``, `DateTime`:
+		if location == nil {
+			return copier, nil
+		}
+		convert = func(value time.Time) interface{} {
+			return inLocation(value, location)
+		}
+	case `LocalDateTime`:
+		convert = func(value time.Time) interface{} {
+			return dbtype.LocalDateTime(inLocation(value, time.UTC))
+		}
+	default:
+		return nil, fmt.Errorf(`the date time type '%v' is not valid, expected 'DateTime' or 'LocalDateTime'`, options.Type)
+	}
+
+	return func(copyFrom sdk.Record, copyTo map[string]interface{}) error {
+		err := copier(copyFrom, copyTo)
+		if err != nil {
+			return err
+		}
+		if value, ok := copyTo[field].(time.Time); ok {
+			copyTo[field] = convert(value)
+		}
+		return nil
+	}, nil
+}
+
+func inLocation(value time.Time, location *time.Location) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), location)
+}
@@ -0,0 +1,64 @@
+package util_test
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"github.com/tlarsendataguy/graphyx/util"
+	"testing"
+	"time"
+)
+
+var wallClock = time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func copyWallClock(_ sdk.Record, copyTo map[string]interface{}) error {
+	copyTo[`Field`] = wallClock
+	return nil
+}
+
+func TestDateTimeCopierInNamedZone(t *testing.T) {
+	copier, err := util.DateTimeCopier(`Field`, copyWallClock, util.DateTimeOptions{Zone: `America/Chicago`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	copyTo := map[string]interface{}{}
+	err = copier(nil, copyTo)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	value, ok := copyTo[`Field`].(time.Time)
+	if !ok {
+		t.Fatalf(`expected a time.Time but got %T`, copyTo[`Field`])
+	}
+	if expected := `2022-03-04T05:06:07-06:00`; value.Format(time.RFC3339) != expected {
+		t.Fatalf(`expected %v but got %v`, expected, value.Format(time.RFC3339))
+	}
+	if value.Location().String() != `America/Chicago` {
+		t.Fatalf(`expected America/Chicago but got %v`, value.Location())
+	}
+}
+
+func TestDateTimeCopierAsLocalDateTime(t *testing.T) {
+	copier, err := util.DateTimeCopier(`Field`, copyWallClock, util.DateTimeOptions{Type: `LocalDateTime`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	copyTo := map[string]interface{}{}
+	err = copier(nil, copyTo)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := dbtype.LocalDateTime(wallClock); copyTo[`Field`] != expected {
+		t.Fatalf(`expected %v but got %v`, expected, copyTo[`Field`])
+	}
+}
+
+func TestDateTimeCopierInvalidOptions(t *testing.T) {
+	_, err := util.DateTimeCopier(`Field`, copyWallClock, util.DateTimeOptions{Zone: `Mars/Olympus_Mons`})
+	if err == nil {
+		t.Fatalf(`expected an error for an invalid zone but got none`)
+	}
+	_, err = util.DateTimeCopier(`Field`, copyWallClock, util.DateTimeOptions{Type: `Zoned`})
+	if err == nil {
+		t.Fatalf(`expected an error for an invalid type but got none`)
+	}
+}
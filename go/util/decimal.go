@@ -0,0 +1,98 @@
+package util
+
+import (
+	"fmt"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strconv"
+	"strings"
+)
+
+type DecimalOptions struct {
+	Field string
+	Mode  string
+	Scale int
+}
+
+func DecimalCopier(options DecimalOptions, incomingInfo sdk.IncomingRecordInfo) (CopyData, error) {
+	field := options.Field
+	// Fields() hides the byte getters, but the cloned fields keep them, so the exact
+	// text of the decimal can be read rather than the float64 the SDK would return.
+	for _, incomingField := range incomingInfo.Clone().Fields() {
+		if incomingField.Name != field {
+			continue
+		}
+		if incomingField.Type != `FixedDecimal` {
+			return nil, fmt.Errorf(`field %v has type '%v' but decimal options can only be used with FixedDecimal fields`, field, incomingField.Type)
+		}
+		scale := options.Scale
+		if scale == 0 {
+			scale = incomingField.Scale
+		}
+		var convert func(string) (interface{}, error)
+		switch options.Mode {
+		case ``, `String`:
+			convert = func(value string) (interface{}, error) { return value, nil }
+		case `ScaledInteger`:
+			convert = func(value string) (interface{}, error) { return ScaleDecimal(value, scale) }
+		default:
+			return nil, fmt.Errorf(`the decimal mode '%v' for field %v is not valid, expected 'String' or 'ScaledInteger'`, options.Mode, field)
+		}
+		size := incomingField.Size
+		getBytes := incomingField.GetBytes
+		return func(copyFrom sdk.Record, copyTo map[string]interface{}) error {
+			bytes := getBytes(copyFrom)
+			if bytes[size] == 1 {
+				copyTo[field] = nil
+				return nil
+			}
+			value, err := convert(decimalText(bytes[:size]))
+			if err != nil {
+				copyTo[field] = nil
+				return fmt.Errorf(`error: field %v: %v`, field, err.Error())
+			}
+			copyTo[field] = value
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf(`field %v was not contained in the record`, field)
+}
+
+func decimalText(bytes []byte) string {
+	for index, b := range bytes {
+		if b == 0 {
+			bytes = bytes[:index]
+			break
+		}
+	}
+	return strings.TrimSpace(string(bytes))
+}
+
+func ScaleDecimal(value string, scale int) (int64, error) {
+	invalid := fmt.Errorf(`'%v' is not a valid decimal`, value)
+	digits := value
+	sign := ``
+	if strings.HasPrefix(digits, `-`) || strings.HasPrefix(digits, `+`) {
+		sign, digits = digits[:1], digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, `.`)
+	if whole == `` && fraction == `` {
+		return 0, invalid
+	}
+	for _, part := range []string{whole, fraction} {
+		if strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+			return 0, invalid
+		}
+	}
+	if len(fraction) > scale {
+		if strings.Trim(fraction[scale:], `0`) != `` {
+			return 0, fmt.Errorf(`'%v' has more than %v decimal places and cannot be scaled exactly`, value, scale)
+		}
+		fraction = fraction[:scale]
+	}
+	fraction += strings.Repeat(`0`, scale-len(fraction))
+	scaled, err := strconv.ParseInt(sign+whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(`'%v' is too large to be scaled by %v decimal places`, value, scale)
+	}
+	return scaled, nil
+}
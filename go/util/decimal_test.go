@@ -0,0 +1,142 @@
+package util_test
+
+import (
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"github.com/tlarsendataguy/graphyx/util"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type decimalCopierPlugin struct {
+	options []util.DecimalOptions
+	copiers []util.CopyData
+	rows    []map[string]interface{}
+	errs    []error
+}
+
+func (p *decimalCopierPlugin) Init(_ sdk.Provider) {}
+
+func (p *decimalCopierPlugin) OnInputConnectionOpened(connection sdk.InputConnection) {
+	for _, options := range p.options {
+		copier, err := util.DecimalCopier(options, connection.Metadata())
+		if err != nil {
+			p.errs = append(p.errs, err)
+			continue
+		}
+		p.copiers = append(p.copiers, copier)
+	}
+}
+
+func (p *decimalCopierPlugin) OnRecordPacket(connection sdk.InputConnection) {
+	packet := connection.Read()
+	for packet.Next() {
+		row := map[string]interface{}{}
+		for _, copier := range p.copiers {
+			err := copier(packet.Record(), row)
+			if err != nil {
+				p.errs = append(p.errs, err)
+			}
+		}
+		p.rows = append(p.rows, row)
+	}
+}
+
+func (p *decimalCopierPlugin) OnComplete() {}
+
+func runDecimalCopier(t *testing.T, options ...util.DecimalOptions) *decimalCopierPlugin {
+	data := `ID   |Name     |Amount           |Rate
+Int64|V_String;20|FixedDecimal;19;2|FixedDecimal;12;4
+1    |"a"      |12345678901234.56|0.1250
+2    |"b"      |-0.07            |3.0000
+3    |"c"      |                 |
+`
+	path := filepath.Join(t.TempDir(), `decimals.txt`)
+	err := os.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	plugin := &decimalCopierPlugin{options: options}
+	runner := sdk.RegisterToolTest(plugin, 1, `<Configuration></Configuration>`)
+	runner.ConnectInput(`Input`, path)
+	runner.SimulateLifecycle()
+	return plugin
+}
+
+func TestDecimalCopierAsString(t *testing.T) {
+	plugin := runDecimalCopier(t, util.DecimalOptions{Field: `Amount`}, util.DecimalOptions{Field: `Rate`, Mode: `String`})
+	if len(plugin.errs) > 0 {
+		t.Fatalf(`expected no errors but got: %v`, plugin.errs)
+	}
+	expected := []map[string]interface{}{
+		{`Amount`: `12345678901234.56`, `Rate`: `0.1250`},
+		{`Amount`: `-0.07`, `Rate`: `3.0000`},
+		{`Amount`: nil, `Rate`: nil},
+	}
+	if !reflect.DeepEqual(expected, plugin.rows) {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, plugin.rows)
+	}
+}
+
+func TestDecimalCopierAsScaledInteger(t *testing.T) {
+	plugin := runDecimalCopier(t, util.DecimalOptions{Field: `Amount`, Mode: `ScaledInteger`}, util.DecimalOptions{Field: `Rate`, Mode: `ScaledInteger`, Scale: 6})
+	if len(plugin.errs) > 0 {
+		t.Fatalf(`expected no errors but got: %v`, plugin.errs)
+	}
+	expected := []map[string]interface{}{
+		{`Amount`: int64(1234567890123456), `Rate`: int64(125000)},
+		{`Amount`: int64(-7), `Rate`: int64(3000000)},
+		{`Amount`: nil, `Rate`: nil},
+	}
+	if !reflect.DeepEqual(expected, plugin.rows) {
+		t.Fatalf("expected\n%v\nbut got\n%v", expected, plugin.rows)
+	}
+}
+
+func TestDecimalCopierRejectsOtherTypes(t *testing.T) {
+	plugin := runDecimalCopier(t, util.DecimalOptions{Field: `Name`}, util.DecimalOptions{Field: `Amount`, Mode: `Rounded`})
+	if len(plugin.errs) != 2 {
+		t.Fatalf(`expected 2 errors but got: %v`, plugin.errs)
+	}
+}
+
+func TestScaleDecimal(t *testing.T) {
+	value, err := util.ScaleDecimal(`12.3`, 2)
+	if err != nil || value != 1230 {
+		t.Fatalf(`expected 1230 but got %v (%v)`, value, err)
+	}
+	value, err = util.ScaleDecimal(`12.300`, 2)
+	if err != nil || value != 1230 {
+		t.Fatalf(`expected 1230 but got %v (%v)`, value, err)
+	}
+	for _, invalid := range []string{`12.345`, `abc`, `1.2.3`, ``, `99999999999999999999`} {
+		_, err = util.ScaleDecimal(invalid, 2)
+		if err == nil {
+			t.Fatalf(`expected an error for '%v' but got none`, invalid)
+		}
+	}
+}
+
+func TestDecimalCopierAfterEveryFixedType(t *testing.T) {
+	data := `Flag|Tiny|Small|Medium|Big  |Single|Double|Code    |Wide     |Day |Moment  |Note       |Amount
+Bool|Byte|Int16|Int32 |Int64|Float |Double|String;4|WString;3|Date|DateTime|V_WString;10|FixedDecimal;19;6
+true|1   |2    |3     |4    |5.5   |6.5   |"abcd"  |"xyz"    |2020-01-02|2020-01-02 03:04:05|"note"|-12345.678901
+`
+	path := filepath.Join(t.TempDir(), `decimals.txt`)
+	err := os.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	plugin := &decimalCopierPlugin{options: []util.DecimalOptions{{Field: `Amount`}}}
+	runner := sdk.RegisterToolTest(plugin, 1, `<Configuration></Configuration>`)
+	runner.ConnectInput(`Input`, path)
+	runner.SimulateLifecycle()
+
+	if len(plugin.errs) > 0 {
+		t.Fatalf(`expected no errors but got: %v`, plugin.errs)
+	}
+	if len(plugin.rows) != 1 || plugin.rows[0][`Amount`] != `-12345.678901` {
+		t.Fatalf(`expected the exact decimal text but got %v`, plugin.rows)
+	}
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"sync"
+)
+
+type OpenDriverFunc func(ctx context.Context, connection ConnectionConfig, auth neo4j.AuthToken) (neo4j.DriverWithContext, error)
+
+type DriverRegistry struct {
+	open    OpenDriverFunc
+	lock    sync.Mutex
+	entries map[string]*registeredDriver
+	keys    map[neo4j.DriverWithContext]string
+}
+
+type registeredDriver struct {
+	driver neo4j.DriverWithContext
+	refs   int
+}
+
+var Drivers = NewDriverRegistry(openVerifiedDriver)
+
+func NewDriverRegistry(open OpenDriverFunc) *DriverRegistry {
+	return &DriverRegistry{
+		open:    open,
+		entries: make(map[string]*registeredDriver),
+		keys:    make(map[neo4j.DriverWithContext]string),
+	}
+}
+
+func AcquireDriver(ctx context.Context, connection ConnectionConfig, provider sdk.Provider) (neo4j.DriverWithContext, error) {
+	auth, err := GetAuthToken(connection.Credentials, provider)
+	if err != nil {
+		return nil, err
+	}
+	return Drivers.Acquire(ctx, connection, auth)
+}
+
+func ReleaseDriver(ctx context.Context, driver neo4j.DriverWithContext) error {
+	return Drivers.Release(ctx, driver)
+}
+
+func (r *DriverRegistry) Acquire(ctx context.Context, connection ConnectionConfig, auth neo4j.AuthToken) (neo4j.DriverWithContext, error) {
+	key, err := driverKey(connection, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if entry, ok := r.entries[key]; ok {
+		entry.refs++
+		return entry.driver, nil
+	}
+	driver, err := r.open(ctx, connection, auth)
+	if err != nil {
+		return nil, err
+	}
+	r.entries[key] = &registeredDriver{driver: driver, refs: 1}
+	r.keys[driver] = key
+	return driver, nil
+}
+
+func (r *DriverRegistry) Release(ctx context.Context, driver neo4j.DriverWithContext) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key, ok := r.keys[driver]
+	if !ok {
+		return nil
+	}
+	entry := r.entries[key]
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+	delete(r.entries, key)
+	delete(r.keys, driver)
+	return driver.Close(ctx)
+}
+
+func (r *DriverRegistry) Count() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.entries)
+}
+
+func driverKey(connection ConnectionConfig, auth neo4j.AuthToken) (string, error) {
+	authJson, err := json.Marshal(auth.Tokens)
+	if err != nil {
+		return ``, err
+	}
+	authHash := sha256.Sum256(authJson)
+	return fmt.Sprintf(`%v|%v|%v|%v|%v|%v|%v|%v`,
+		connection.Credentials.Url,
+		hex.EncodeToString(authHash[:]),
+		connection.TlsCaFile,
+		connection.TlsSkipVerify,
+		connection.MaxConnectionPoolSize,
+		connection.MaxTransactionRetrySeconds,
+		connection.ConnectionAcquisitionTimeoutSeconds,
+		connection.UserAgent,
+	), nil
+}
+
+func openVerifiedDriver(ctx context.Context, connection ConnectionConfig, auth neo4j.AuthToken) (neo4j.DriverWithContext, error) {
+	url, configurer, err := ConfigureDriver(connection)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := neo4j.NewDriverWithContext(url, auth, configurer)
+	if err != nil {
+		return nil, err
+	}
+	err = driver.VerifyConnectivity(ctx)
+	if err != nil {
+		_ = driver.Close(ctx)
+		return nil, err
+	}
+	return driver, nil
+}
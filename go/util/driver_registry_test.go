@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"context"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/graphyx/util"
+	"testing"
+)
+
+func newTestRegistry(opened *int) *util.DriverRegistry {
+	return util.NewDriverRegistry(func(ctx context.Context, connection util.ConnectionConfig, auth neo4j.AuthToken) (neo4j.DriverWithContext, error) {
+		*opened++
+		return neo4j.NewDriverWithContext(connection.Credentials.Url, auth)
+	})
+}
+
+func TestDriverRegistrySharesDrivers(t *testing.T) {
+	ctx := context.Background()
+	opened := 0
+	registry := newTestRegistry(&opened)
+	connection := util.ConnectionConfig{Credentials: util.CredentialSource{Url: testUrl}}
+	auth := neo4j.BasicAuth(`user`, `pass`, ``)
+
+	first, err := registry.Acquire(ctx, connection, auth)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	second, err := registry.Acquire(ctx, connection, auth)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if first != second {
+		t.Fatalf(`expected the same driver to be shared`)
+	}
+	if opened != 1 {
+		t.Fatalf(`expected 1 driver to be opened but got %v`, opened)
+	}
+
+	_ = registry.Release(ctx, first)
+	if count := registry.Count(); count != 1 {
+		t.Fatalf(`expected the driver to stay open while referenced but got %v drivers`, count)
+	}
+	_ = registry.Release(ctx, second)
+	if count := registry.Count(); count != 0 {
+		t.Fatalf(`expected the driver to be closed after the last release but got %v drivers`, count)
+	}
+}
+
+func TestDriverRegistrySeparatesConnections(t *testing.T) {
+	ctx := context.Background()
+	opened := 0
+	registry := newTestRegistry(&opened)
+	connection := util.ConnectionConfig{Credentials: util.CredentialSource{Url: testUrl}}
+
+	_, _ = registry.Acquire(ctx, connection, neo4j.BasicAuth(`user`, `pass`, ``))
+	_, _ = registry.Acquire(ctx, connection, neo4j.BasicAuth(`other`, `pass`, ``))
+	connection.TlsSkipVerify = true
+	_, _ = registry.Acquire(ctx, connection, neo4j.BasicAuth(`user`, `pass`, ``))
+	connection = util.ConnectionConfig{Credentials: util.CredentialSource{Url: `bolt://otherhost:7687`}}
+	_, _ = registry.Acquire(ctx, connection, neo4j.BasicAuth(`user`, `pass`, ``))
+
+	if opened != 4 {
+		t.Fatalf(`expected 4 drivers to be opened but got %v`, opened)
+	}
+	if count := registry.Count(); count != 4 {
+		t.Fatalf(`expected 4 registered drivers but got %v`, count)
+	}
+}
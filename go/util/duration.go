@@ -0,0 +1,136 @@
+package util
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strconv"
+	"strings"
+)
+
+func DurationCopier(field string, copier CopyData) CopyData {
+	return func(copyFrom sdk.Record, copyTo map[string]interface{}) error {
+		err := copier(copyFrom, copyTo)
+		if err != nil {
+			return err
+		}
+		value := copyTo[field]
+		if value == nil {
+			return nil
+		}
+		text, ok := value.(string)
+		if !ok {
+			copyTo[field] = nil
+			return fmt.Errorf(`field %v must be a string to be sent as a duration`, field)
+		}
+		if text == `` {
+			copyTo[field] = nil
+			return nil
+		}
+		duration, err := ParseIsoDuration(text)
+		if err != nil {
+			copyTo[field] = nil
+			return fmt.Errorf(`field %v: %v`, field, err.Error())
+		}
+		copyTo[field] = duration
+		return nil
+	}
+}
+
+func ParseIsoDuration(value string) (dbtype.Duration, error) {
+	invalid := fmt.Errorf(`'%v' is not a valid ISO-8601 duration`, value)
+	text := strings.ToUpper(strings.TrimSpace(value))
+	negative := strings.HasPrefix(text, `-`)
+	text = strings.TrimPrefix(strings.TrimPrefix(text, `-`), `+`)
+	if !strings.HasPrefix(text, `P`) || len(text) == 1 {
+		return dbtype.Duration{}, invalid
+	}
+	text = text[1:]
+
+	var months, days, seconds, nanos int64
+	inTime := false
+	lastTimeUnit := false
+	for len(text) > 0 {
+		if text[0] == 'T' {
+			if inTime || len(text) == 1 {
+				return dbtype.Duration{}, invalid
+			}
+			inTime = true
+			text = text[1:]
+			continue
+		}
+		end := strings.IndexFunc(text, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ',' && r != '-' && r != '+'
+		})
+		if end <= 0 {
+			return dbtype.Duration{}, invalid
+		}
+		number, unit := strings.Replace(text[:end], `,`, `.`, 1), text[end]
+		text = text[end+1:]
+		if lastTimeUnit {
+			return dbtype.Duration{}, invalid
+		}
+
+		if unit == 'S' && inTime {
+			whole, fraction, err := parseSeconds(number)
+			if err != nil {
+				return dbtype.Duration{}, invalid
+			}
+			seconds += whole
+			nanos += fraction
+			lastTimeUnit = true
+			continue
+		}
+		amount, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return dbtype.Duration{}, invalid
+		}
+		switch {
+		case unit == 'Y' && !inTime:
+			months += amount * 12
+		case unit == 'M' && !inTime:
+			months += amount
+		case unit == 'W' && !inTime:
+			days += amount * 7
+		case unit == 'D' && !inTime:
+			days += amount
+		case unit == 'H' && inTime:
+			seconds += amount * 3600
+		case unit == 'M' && inTime:
+			seconds += amount * 60
+		default:
+			return dbtype.Duration{}, invalid
+		}
+	}
+
+	if negative {
+		months, days, seconds, nanos = -months, -days, -seconds, -nanos
+	}
+	if nanos < 0 {
+		seconds--
+		nanos += 1000000000
+	}
+	return dbtype.Duration{Months: months, Days: days, Seconds: seconds, Nanos: int(nanos)}, nil
+}
+
+func parseSeconds(number string) (int64, int64, error) {
+	wholeText, fractionText, hasFraction := strings.Cut(number, `.`)
+	whole, err := strconv.ParseInt(wholeText, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasFraction {
+		return whole, 0, nil
+	}
+	if len(fractionText) == 0 || len(fractionText) > 9 {
+		return 0, 0, fmt.Errorf(`invalid fraction`)
+	}
+	fraction, err := strconv.ParseInt(fractionText+strings.Repeat(`0`, 9-len(fractionText)), 10, 64)
+	if err != nil || fraction < 0 {
+		return 0, 0, fmt.Errorf(`invalid fraction`)
+	}
+	if strings.HasPrefix(wholeText, `-`) {
+		fraction = -fraction
+	}
+	return whole, fraction, nil
+}
@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/graphyx/util"
+	"testing"
+)
+
+func TestParseIsoDuration(t *testing.T) {
+	cases := map[string]dbtype.Duration{
+		`P1Y2M`:                {Months: 14},
+		`P2W3D`:                {Days: 17},
+		`PT1H30M`:              {Seconds: 5400},
+		`PT0.5S`:               {Nanos: 500000000},
+		`P1DT2H3M4.000000005S`: {Days: 1, Seconds: 7384, Nanos: 5},
+		`-PT1.5S`:              {Seconds: -2, Nanos: 500000000},
+		`pt4h`:                 {Seconds: 14400},
+	}
+	for text, expected := range cases {
+		duration, err := util.ParseIsoDuration(text)
+		if err != nil {
+			t.Fatalf(`expected no error for '%v' but got: %v`, text, err.Error())
+		}
+		if !duration.Equal(expected) {
+			t.Fatalf(`expected %v for '%v' but got %v`, expected, text, duration)
+		}
+	}
+}
+
+func TestParseIsoDurationRoundTrip(t *testing.T) {
+	expected := dbtype.Duration{Months: 3, Days: -4, Seconds: 61, Nanos: 250000000}
+	duration, err := util.ParseIsoDuration(expected.String())
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if !duration.Equal(expected) {
+		t.Fatalf(`expected %v but got %v`, expected, duration)
+	}
+}
+
+func TestParseInvalidIsoDuration(t *testing.T) {
+	for _, text := range []string{``, `P`, `1D`, `PT`, `P1H`, `PT1D`, `P1.5D`, `PT1S2M`, `P1DT`} {
+		_, err := util.ParseIsoDuration(text)
+		if err == nil {
+			t.Fatalf(`expected an error for '%v' but got none`, text)
+		}
+	}
+}
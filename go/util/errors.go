@@ -0,0 +1,31 @@
+package util
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func Neo4jErrorDetails(err error) (string, string) {
+	switch typed := err.(type) {
+	case *neo4j.Neo4jError:
+		return typed.Code, typed.Msg
+	case *neo4j.TransactionExecutionLimit:
+		if len(typed.Errors) > 0 {
+			code, _ := Neo4jErrorDetails(typed.Errors[len(typed.Errors)-1])
+			return code, typed.Error()
+		}
+	}
+	return ``, err.Error()
+}
+
+type UnsupportedFieldTypeError struct {
+	Field string
+	Type  string
+}
+
+func (e *UnsupportedFieldTypeError) Error() string {
+	if e.Type == `Time` {
+		return fmt.Sprintf(`field %v has type 'Time', which cannot be sent to Neo4j because the Alteryx SDK cannot read Time fields; convert it to a string or DateTime first`, e.Field)
+	}
+	return fmt.Sprintf(`field %v has type '%v', which cannot be sent to Neo4j`, e.Field, e.Type)
+}
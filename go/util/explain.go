@@ -0,0 +1,86 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"strings"
+)
+
+type ExplainResult struct {
+	Query     string
+	Operators []string
+	Warnings  []string
+}
+
+var scanWarnings = map[string]string{
+	`AllNodesScan`:     `the plan scans all nodes; add a label to the pattern`,
+	`NodeByLabelScan`:  `the plan scans every node with the label, which usually means the lookup or MERGE keys are not indexed`,
+	`CartesianProduct`: `the plan contains a Cartesian product between disconnected patterns`,
+	`Eager`:            `the plan contains an Eager operator, which buffers the whole batch in memory`,
+}
+
+func Explain(ctx context.Context, session neo4j.SessionWithContext, query string, params map[string]interface{}) (ExplainResult, error) {
+	explained := ExplainResult{Query: query}
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		return explained, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Run(ctx, `EXPLAIN `+query, params)
+	if err != nil {
+		return explained, err
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return explained, err
+	}
+	for _, notification := range summary.Notifications() {
+		explained.Warnings = append(explained.Warnings, fmt.Sprintf(`%v (%v): %v`, notification.Title(), notification.Code(), notification.Description()))
+	}
+	operators, warnings := DescribePlan(summary.Plan())
+	explained.Operators = operators
+	explained.Warnings = append(explained.Warnings, warnings...)
+	return explained, nil
+}
+
+func DescribePlan(plan neo4j.Plan) ([]string, []string) {
+	described := &ExplainResult{}
+	if plan != nil {
+		described.addPlan(plan, 0, make(map[string]bool))
+	}
+	return described.Operators, described.Warnings
+}
+
+func (e *ExplainResult) addPlan(plan neo4j.Plan, depth int, warned map[string]bool) {
+	operator := PlanOperatorName(plan.Operator())
+	line := strings.Repeat(`  `, depth) + operator
+	if details, ok := plan.Arguments()[`Details`]; ok && details != `` {
+		line = fmt.Sprintf(`%v %v`, line, details)
+	}
+	e.Operators = append(e.Operators, line)
+	if warning, ok := scanWarnings[operator]; ok && !warned[operator] {
+		warned[operator] = true
+		e.Warnings = append(e.Warnings, fmt.Sprintf(`%v: %v`, operator, warning))
+	}
+	for _, child := range plan.Children() {
+		e.addPlan(child, depth+1, warned)
+	}
+}
+
+func PlanOperatorName(operator string) string {
+	if index := strings.Index(operator, `@`); index >= 0 {
+		return operator[:index]
+	}
+	return operator
+}
+
+func (e ExplainResult) Report(io sdk.Io) {
+	io.Info(`Dry run query:` + "\n" + e.Query)
+	io.Info(`Dry run plan:` + "\n" + strings.Join(e.Operators, "\n"))
+	for _, warning := range e.Warnings {
+		io.Warn(`Dry run warning: ` + warning)
+	}
+}
@@ -0,0 +1,63 @@
+package util_test
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/graphyx/util"
+	"reflect"
+	"testing"
+)
+
+type testPlan struct {
+	operator string
+	details  string
+	children []neo4j.Plan
+}
+
+func (p testPlan) Operator() string { return p.operator }
+
+func (p testPlan) Arguments() map[string]any {
+	if p.details == `` {
+		return map[string]any{}
+	}
+	return map[string]any{`Details`: p.details}
+}
+
+func (p testPlan) Identifiers() []string { return nil }
+
+func (p testPlan) Children() []neo4j.Plan { return p.children }
+
+func TestDescribePlan(t *testing.T) {
+	plan := testPlan{operator: `ProduceResults@neo4j`, children: []neo4j.Plan{
+		testPlan{operator: `Merge@neo4j`, details: `MERGE (n:Customer {id: row.id})`, children: []neo4j.Plan{
+			testPlan{operator: `NodeByLabelScan@neo4j`, details: `n:Customer`},
+			testPlan{operator: `NodeByLabelScan@neo4j`, details: `n:Customer`},
+		}},
+	}}
+	operators, warnings := util.DescribePlan(plan)
+	expectedOperators := []string{
+		`ProduceResults`,
+		`  Merge MERGE (n:Customer {id: row.id})`,
+		`    NodeByLabelScan n:Customer`,
+		`    NodeByLabelScan n:Customer`,
+	}
+	if !reflect.DeepEqual(expectedOperators, operators) {
+		t.Fatalf("expected\n%v\nbut got\n%v", expectedOperators, operators)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf(`expected 1 warning but got %v`, warnings)
+	}
+}
+
+func TestDescribeIndexedPlanHasNoWarnings(t *testing.T) {
+	plan := testPlan{operator: `Merge@neo4j`, children: []neo4j.Plan{
+		testPlan{operator: `NodeUniqueIndexSeek@neo4j`},
+	}}
+	_, warnings := util.DescribePlan(plan)
+	if len(warnings) != 0 {
+		t.Fatalf(`expected no warnings but got %v`, warnings)
+	}
+	_, warnings = util.DescribePlan(nil)
+	if len(warnings) != 0 {
+		t.Fatalf(`expected no warnings but got %v`, warnings)
+	}
+}
@@ -0,0 +1,170 @@
+package util
+
+import (
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ListOptions struct {
+	Field     string
+	Delimiter string
+	Type      string
+}
+
+type convertListItem func(interface{}) (interface{}, error)
+
+func ListCopier(options ListOptions, copier CopyData) (CopyData, error) {
+	convert, err := listItemConverter(options.Type)
+	if err != nil {
+		return nil, fmt.Errorf(`list field %v: %v`, options.Field, err.Error())
+	}
+	field := options.Field
+	return func(copyFrom sdk.Record, copyTo map[string]interface{}) error {
+		err := copier(copyFrom, copyTo)
+		if err != nil {
+			return err
+		}
+		var items []interface{}
+		switch value := copyTo[field].(type) {
+		case nil:
+			return nil
+		case string:
+			if options.Delimiter == `` {
+				copyTo[field] = nil
+				return fmt.Errorf(`error: field %v is a string but no list delimiter was provided`, field)
+			}
+			items = splitList(value, options.Delimiter)
+		case []interface{}:
+			items = value
+		default:
+			copyTo[field] = nil
+			return fmt.Errorf(`error: field %v cannot be converted to a list because it is %T`, field, value)
+		}
+		list := make([]interface{}, len(items))
+		for index, item := range items {
+			list[index], err = convert(item)
+			if err != nil {
+				copyTo[field] = nil
+				return fmt.Errorf(`error: item %v of list field %v: %v`, index, field, err.Error())
+			}
+		}
+		copyTo[field] = list
+		return nil
+	}, nil
+}
+
+func splitList(value string, delimiter string) []interface{} {
+	if strings.TrimSpace(value) == `` {
+		return []interface{}{}
+	}
+	parts := strings.Split(value, delimiter)
+	items := make([]interface{}, len(parts))
+	for index, part := range parts {
+		items[index] = strings.TrimSpace(part)
+	}
+	return items
+}
+
+func listItemConverter(itemType string) (convertListItem, error) {
+	switch itemType {
+	case ``:
+		return func(item interface{}) (interface{}, error) {
+			if item == nil {
+				return nil, fmt.Errorf(`lists cannot contain null values`)
+			}
+			return item, nil
+		}, nil
+	case `String`:
+		return toListString, nil
+	case `Integer`:
+		return toListInteger, nil
+	case `Float`:
+		return toListFloat, nil
+	case `Boolean`:
+		return toListBoolean, nil
+	case `Date`:
+		return toListDate, nil
+	default:
+		return nil, fmt.Errorf(`the list type '%v' is not valid, expected 'String', 'Integer', 'Float', 'Boolean', or 'Date'`, itemType)
+	}
+}
+
+func toListString(item interface{}) (interface{}, error) {
+	switch typed := item.(type) {
+	case string:
+		return typed, nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case nil:
+		return nil, fmt.Errorf(`lists cannot contain null values`)
+	default:
+		return fmt.Sprint(typed), nil
+	}
+}
+
+func toListInteger(item interface{}) (interface{}, error) {
+	switch typed := item.(type) {
+	case string:
+		value, err := strconv.ParseInt(typed, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf(`'%v' is not an integer`, typed)
+		}
+		return value, nil
+	case float64:
+		if typed != math.Trunc(typed) {
+			return nil, fmt.Errorf(`%v is not an integer`, typed)
+		}
+		return int64(typed), nil
+	default:
+		return nil, fmt.Errorf(`%v (%T) cannot be converted to an integer`, typed, typed)
+	}
+}
+
+func toListFloat(item interface{}) (interface{}, error) {
+	switch typed := item.(type) {
+	case string:
+		value, err := strconv.ParseFloat(typed, 64)
+		if err != nil {
+			return nil, fmt.Errorf(`'%v' is not a number`, typed)
+		}
+		return value, nil
+	case float64:
+		return typed, nil
+	default:
+		return nil, fmt.Errorf(`%v (%T) cannot be converted to a float`, typed, typed)
+	}
+}
+
+func toListBoolean(item interface{}) (interface{}, error) {
+	switch typed := item.(type) {
+	case string:
+		value, err := strconv.ParseBool(typed)
+		if err != nil {
+			return nil, fmt.Errorf(`'%v' is not a boolean`, typed)
+		}
+		return value, nil
+	case bool:
+		return typed, nil
+	default:
+		return nil, fmt.Errorf(`%v (%T) cannot be converted to a boolean`, typed, typed)
+	}
+}
+
+func toListDate(item interface{}) (interface{}, error) {
+	text, ok := item.(string)
+	if !ok {
+		return nil, fmt.Errorf(`%v (%T) cannot be converted to a date`, item, item)
+	}
+	value, err := time.Parse(`2006-01-02`, text)
+	if err != nil {
+		return nil, fmt.Errorf(`'%v' is not a date in the format yyyy-MM-dd`, text)
+	}
+	return dbtype.Date(value), nil
+}
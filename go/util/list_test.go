@@ -0,0 +1,92 @@
+package util_test
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"github.com/tlarsendataguy/graphyx/util"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func copyValue(value interface{}) util.CopyData {
+	return func(_ sdk.Record, copyTo map[string]interface{}) error {
+		copyTo[`Field`] = value
+		return nil
+	}
+}
+
+func copyList(t *testing.T, options util.ListOptions, value interface{}) (interface{}, error) {
+	options.Field = `Field`
+	copier, err := util.ListCopier(options, copyValue(value))
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	copyTo := map[string]interface{}{}
+	err = copier(nil, copyTo)
+	return copyTo[`Field`], err
+}
+
+func TestSplitDelimitedStringIntoTypedList(t *testing.T) {
+	cases := []struct {
+		options  util.ListOptions
+		value    string
+		expected interface{}
+	}{
+		{util.ListOptions{Delimiter: `,`, Type: `String`}, `a, b,c`, []interface{}{`a`, `b`, `c`}},
+		{util.ListOptions{Delimiter: `|`, Type: `Integer`}, `1|2|3`, []interface{}{int64(1), int64(2), int64(3)}},
+		{util.ListOptions{Delimiter: `;`, Type: `Float`}, `1.5;2`, []interface{}{1.5, 2.0}},
+		{util.ListOptions{Delimiter: `,`, Type: `Boolean`}, `true,false`, []interface{}{true, false}},
+		{util.ListOptions{Delimiter: `,`, Type: `Date`}, `2022-01-02`, []interface{}{dbtype.Date(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC))}},
+		{util.ListOptions{Delimiter: `,`}, ``, []interface{}{}},
+	}
+	for _, testCase := range cases {
+		list, err := copyList(t, testCase.options, testCase.value)
+		if err != nil {
+			t.Fatalf(`expected no error for '%v' but got: %v`, testCase.value, err.Error())
+		}
+		if !reflect.DeepEqual(testCase.expected, list) {
+			t.Fatalf(`expected %v but got %v`, testCase.expected, list)
+		}
+	}
+}
+
+func TestCoerceJsonListItems(t *testing.T) {
+	list, err := copyList(t, util.ListOptions{Type: `String`}, []interface{}{`a`, 1.0, 2.5, true})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := []interface{}{`a`, `1`, `2.5`, `true`}; !reflect.DeepEqual(expected, list) {
+		t.Fatalf(`expected %v but got %v`, expected, list)
+	}
+
+	list, err = copyList(t, util.ListOptions{Type: `Integer`}, []interface{}{1.0, `2`})
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := []interface{}{int64(1), int64(2)}; !reflect.DeepEqual(expected, list) {
+		t.Fatalf(`expected %v but got %v`, expected, list)
+	}
+}
+
+func TestListCopierErrors(t *testing.T) {
+	list, err := copyList(t, util.ListOptions{Delimiter: `,`, Type: `Integer`}, `1,two`)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+	if list != nil {
+		t.Fatalf(`expected a null value but got %v`, list)
+	}
+	_, err = copyList(t, util.ListOptions{Type: `String`}, `a,b`)
+	if err == nil {
+		t.Fatalf(`expected an error for a missing delimiter but got none`)
+	}
+	_, err = copyList(t, util.ListOptions{}, []interface{}{`a`, nil})
+	if err == nil {
+		t.Fatalf(`expected an error for a null item but got none`)
+	}
+	_, err = util.ListCopier(util.ListOptions{Field: `Field`, Type: `Map`}, copyValue(nil))
+	if err == nil {
+		t.Fatalf(`expected an error for an invalid type but got none`)
+	}
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const deadlockCode = `Neo.TransientError.Transaction.DeadlockDetected`
+
+type RetryPolicy struct {
+	MaxAttempts            int
+	BackoffMilliseconds    int
+	MaxBackoffMilliseconds int
+	Jitter                 float64
+	SplitAfterDeadlocks    int
+}
+
+type RetryFailure struct {
+	Rows []map[string]interface{}
+	Err  error
+}
+
+func IsDeadlock(err error) bool {
+	code, _ := Neo4jErrorDetails(err)
+	return code == deadlockCode
+}
+
+func IsTransientError(err error) bool {
+	code, _ := Neo4jErrorDetails(err)
+	return strings.HasPrefix(code, `Neo.TransientError.`)
+}
+
+func (p RetryPolicy) Run(ctx context.Context, rows []map[string]interface{}, write func([]map[string]interface{}) error) []RetryFailure {
+	deadlocks := 0
+	for attempt := 1; ; attempt++ {
+		err := write(rows)
+		if err == nil {
+			return nil
+		}
+		if !IsTransientError(err) || attempt >= p.MaxAttempts {
+			return []RetryFailure{{Rows: rows, Err: err}}
+		}
+		if IsDeadlock(err) {
+			deadlocks++
+		} else {
+			deadlocks = 0
+		}
+		if p.SplitAfterDeadlocks > 0 && deadlocks >= p.SplitAfterDeadlocks && len(rows) > 1 {
+			middle := len(rows) / 2
+			failures := p.Run(ctx, rows[:middle], write)
+			return append(failures, p.Run(ctx, rows[middle:], write)...)
+		}
+		select {
+		case <-ctx.Done():
+			return []RetryFailure{{Rows: rows, Err: err}}
+		case <-time.After(p.Backoff(attempt, rand.Float64())):
+		}
+	}
+}
+
+func (p RetryPolicy) Backoff(attempt int, random float64) time.Duration {
+	base := time.Duration(p.BackoffMilliseconds) * time.Millisecond
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	limit := time.Duration(p.MaxBackoffMilliseconds) * time.Millisecond
+	if limit <= 0 {
+		limit = 5 * time.Second
+	}
+	delay := base
+	for step := 1; step < attempt && delay < limit; step++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	return delay + time.Duration(float64(delay)*p.Jitter*random)
+}
@@ -0,0 +1,132 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tlarsendataguy/graphyx/util"
+	"testing"
+	"time"
+)
+
+var deadlock = &neo4j.Neo4jError{Code: `Neo.TransientError.Transaction.DeadlockDetected`, Msg: `deadlock`}
+
+func testRows(count int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, count)
+	for index := range rows {
+		rows[index] = map[string]interface{}{`ID`: int64(index)}
+	}
+	return rows
+}
+
+func TestRetryPolicyRetriesTransientErrors(t *testing.T) {
+	policy := util.RetryPolicy{MaxAttempts: 3, BackoffMilliseconds: 1}
+	attempts := 0
+	failures := policy.Run(context.Background(), testRows(4), func(rows []map[string]interface{}) error {
+		attempts++
+		if attempts < 3 {
+			return deadlock
+		}
+		return nil
+	})
+	if len(failures) != 0 {
+		t.Fatalf(`expected no failures but got %v`, failures)
+	}
+	if attempts != 3 {
+		t.Fatalf(`expected 3 attempts but got %v`, attempts)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryOtherErrors(t *testing.T) {
+	policy := util.RetryPolicy{MaxAttempts: 3, BackoffMilliseconds: 1}
+	attempts := 0
+	constraint := &neo4j.Neo4jError{Code: `Neo.ClientError.Schema.ConstraintValidationFailed`, Msg: `already exists`}
+	failures := policy.Run(context.Background(), testRows(4), func(rows []map[string]interface{}) error {
+		attempts++
+		return constraint
+	})
+	if attempts != 1 {
+		t.Fatalf(`expected 1 attempt but got %v`, attempts)
+	}
+	if len(failures) != 1 || len(failures[0].Rows) != 4 || !errors.Is(failures[0].Err, constraint) {
+		t.Fatalf(`expected one failure covering 4 rows but got %v`, failures)
+	}
+}
+
+func TestRetryPolicyGivesUpAfterMaxAttempts(t *testing.T) {
+	policy := util.RetryPolicy{MaxAttempts: 2, BackoffMilliseconds: 1}
+	attempts := 0
+	failures := policy.Run(context.Background(), testRows(4), func(rows []map[string]interface{}) error {
+		attempts++
+		return deadlock
+	})
+	if attempts != 2 {
+		t.Fatalf(`expected 2 attempts but got %v`, attempts)
+	}
+	if len(failures) != 1 || !util.IsDeadlock(failures[0].Err) {
+		t.Fatalf(`expected one deadlock failure but got %v`, failures)
+	}
+}
+
+func TestRetryPolicySplitsAfterDeadlocks(t *testing.T) {
+	policy := util.RetryPolicy{MaxAttempts: 3, BackoffMilliseconds: 1, SplitAfterDeadlocks: 2}
+	var written [][]map[string]interface{}
+	failures := policy.Run(context.Background(), testRows(4), func(rows []map[string]interface{}) error {
+		if len(rows) > 1 {
+			return deadlock
+		}
+		written = append(written, rows)
+		return nil
+	})
+	if len(failures) != 0 {
+		t.Fatalf(`expected no failures but got %v`, failures)
+	}
+	if len(written) != 4 {
+		t.Fatalf(`expected the batch to be split into 4 single rows but got %v writes`, len(written))
+	}
+	for index, rows := range written {
+		if id := rows[0][`ID`]; id != int64(index) {
+			t.Fatalf(`expected row %v to be written in order but got %v`, index, id)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := util.RetryPolicy{BackoffMilliseconds: 100, MaxBackoffMilliseconds: 350, Jitter: 0.5}
+	cases := []struct {
+		attempt  int
+		random   float64
+		expected time.Duration
+	}{
+		{1, 0, 100 * time.Millisecond},
+		{2, 0, 200 * time.Millisecond},
+		{3, 0, 350 * time.Millisecond},
+		{10, 0, 350 * time.Millisecond},
+		{1, 1, 150 * time.Millisecond},
+		{2, 0.5, 250 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if actual := policy.Backoff(c.attempt, c.random); actual != c.expected {
+			t.Fatalf(`expected %v for attempt %v but got %v`, c.expected, c.attempt, actual)
+		}
+	}
+}
+
+func TestSortRowsByFields(t *testing.T) {
+	rows := []map[string]interface{}{
+		{`Left`: `b`, `Right`: int64(2)},
+		{`Left`: `a`, `Right`: int64(10)},
+		{`Left`: nil, `Right`: int64(5)},
+		{`Left`: `b`, `Right`: int64(1)},
+		{`Left`: `a`, `Right`: int64(9)},
+	}
+	util.SortRowsByFields(rows, []string{`Left`, `Right`})
+	expected := []string{`<nil> 5`, `a 9`, `a 10`, `b 1`, `b 2`}
+	for index, row := range rows {
+		actual := fmt.Sprintf(`%v %v`, row[`Left`], row[`Right`])
+		if actual != expected[index] {
+			t.Fatalf(`expected '%v' at %v but got '%v'`, expected[index], index, actual)
+		}
+	}
+}
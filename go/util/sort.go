@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+func SortRowsByFields(rows []map[string]interface{}, fields []string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, field := range fields {
+			compared := compareValues(rows[i][field], rows[j][field])
+			if compared != 0 {
+				return compared < 0
+			}
+		}
+		return false
+	})
+}
+
+func compareValues(left interface{}, right interface{}) int {
+	if left == nil || right == nil {
+		switch {
+		case left == nil && right == nil:
+			return 0
+		case left == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch l := left.(type) {
+	case int64:
+		if r, ok := right.(int64); ok {
+			return compareInts(l, r)
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareFloats(l, r)
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r)
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			return compareInts(boolRank(l), boolRank(r))
+		}
+	case time.Time:
+		if r, ok := right.(time.Time); ok {
+			return compareInts(l.UnixNano(), r.UnixNano())
+		}
+	}
+	return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+}
+
+func compareInts(left int64, right int64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(left float64, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+	return 0
+}
+
+func boolRank(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,222 @@
+package util
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const wgs84 = 4326
+
+const (
+	shapePoint      = 1
+	shapePolyLine   = 3
+	shapePolygon    = 5
+	shapeMultiPoint = 8
+)
+
+var errShortSpatialObj = errors.New(`the spatial object is shorter than its shape requires`)
+
+type spatialPoint struct {
+	x float64
+	y float64
+}
+
+func SpatialObjToPoint(blob []byte) (dbtype.Point2D, error) {
+	shapeType, err := spatialShapeType(blob)
+	if err != nil {
+		return dbtype.Point2D{}, err
+	}
+	if shapeType != shapePoint {
+		return dbtype.Point2D{}, fmt.Errorf(`the spatial object is a %v and cannot be written as a point`, shapeName(shapeType))
+	}
+	point, err := readSpatialPoint(blob, 4)
+	if err != nil {
+		return dbtype.Point2D{}, err
+	}
+	return dbtype.Point2D{X: point.x, Y: point.y, SpatialRefId: wgs84}, nil
+}
+
+func SpatialObjToWKT(blob []byte) (string, error) {
+	shapeType, err := spatialShapeType(blob)
+	if err != nil {
+		return ``, err
+	}
+	switch shapeType {
+	case shapePoint:
+		point, err := readSpatialPoint(blob, 4)
+		if err != nil {
+			return ``, err
+		}
+		return `POINT ` + wktPoints([]spatialPoint{point}), nil
+	case shapeMultiPoint:
+		points, err := readSpatialPoints(blob, 36, 40)
+		if err != nil {
+			return ``, err
+		}
+		parts := make([]string, len(points))
+		for index, point := range points {
+			parts[index] = wktPoints([]spatialPoint{point})
+		}
+		return `MULTIPOINT (` + strings.Join(parts, `, `) + `)`, nil
+	case shapePolyLine:
+		lines, err := readSpatialParts(blob)
+		if err != nil {
+			return ``, err
+		}
+		if len(lines) == 1 {
+			return `LINESTRING ` + wktPoints(lines[0]), nil
+		}
+		return `MULTILINESTRING ` + wktList(lines), nil
+	case shapePolygon:
+		rings, err := readSpatialParts(blob)
+		if err != nil {
+			return ``, err
+		}
+		polygons := groupRings(rings)
+		if len(polygons) == 1 {
+			return `POLYGON ` + wktList(polygons[0]), nil
+		}
+		wktPolygons := make([]string, len(polygons))
+		for index, polygon := range polygons {
+			wktPolygons[index] = wktList(polygon)
+		}
+		return `MULTIPOLYGON (` + strings.Join(wktPolygons, `, `) + `)`, nil
+	default:
+		return ``, fmt.Errorf(`the spatial object is a %v, which is not supported`, shapeName(shapeType))
+	}
+}
+
+func spatialShapeType(blob []byte) (int32, error) {
+	if len(blob) < 4 {
+		return 0, errShortSpatialObj
+	}
+	return int32(binary.LittleEndian.Uint32(blob)), nil
+}
+
+func shapeName(shapeType int32) string {
+	switch shapeType {
+	case shapePoint:
+		return `point`
+	case shapePolyLine:
+		return `polyline`
+	case shapePolygon:
+		return `polygon`
+	case shapeMultiPoint:
+		return `multipoint`
+	default:
+		return fmt.Sprintf(`shape of type %v`, shapeType)
+	}
+}
+
+func readSpatialPoint(blob []byte, offset int) (spatialPoint, error) {
+	if len(blob) < offset+16 {
+		return spatialPoint{}, errShortSpatialObj
+	}
+	return spatialPoint{
+		x: math.Float64frombits(binary.LittleEndian.Uint64(blob[offset:])),
+		y: math.Float64frombits(binary.LittleEndian.Uint64(blob[offset+8:])),
+	}, nil
+}
+
+func readInt32(blob []byte, offset int) (int, error) {
+	if len(blob) < offset+4 {
+		return 0, errShortSpatialObj
+	}
+	value := int(int32(binary.LittleEndian.Uint32(blob[offset:])))
+	if value < 0 {
+		return 0, fmt.Errorf(`the spatial object has a negative count at byte %v`, offset)
+	}
+	return value, nil
+}
+
+func readSpatialPoints(blob []byte, countOffset int, pointsOffset int) ([]spatialPoint, error) {
+	count, err := readInt32(blob, countOffset)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) < pointsOffset+count*16 {
+		return nil, errShortSpatialObj
+	}
+	points := make([]spatialPoint, count)
+	for index := range points {
+		points[index], _ = readSpatialPoint(blob, pointsOffset+index*16)
+	}
+	return points, nil
+}
+
+func readSpatialParts(blob []byte) ([][]spatialPoint, error) {
+	numParts, err := readInt32(blob, 36)
+	if err != nil {
+		return nil, err
+	}
+	pointsOffset := 44 + numParts*4
+	points, err := readSpatialPoints(blob, 40, pointsOffset)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([][]spatialPoint, numParts)
+	for index := range parts {
+		start, err := readInt32(blob, 44+index*4)
+		if err != nil {
+			return nil, err
+		}
+		end := len(points)
+		if index+1 < numParts {
+			end, err = readInt32(blob, 48+index*4)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if start > end || end > len(points) {
+			return nil, fmt.Errorf(`the spatial object has an invalid part index %v`, start)
+		}
+		parts[index] = points[start:end]
+	}
+	return parts, nil
+}
+
+func groupRings(rings [][]spatialPoint) [][][]spatialPoint {
+	var polygons [][][]spatialPoint
+	for _, ring := range rings {
+		if len(polygons) == 0 || isClockwise(ring) {
+			polygons = append(polygons, [][]spatialPoint{ring})
+			continue
+		}
+		last := len(polygons) - 1
+		polygons[last] = append(polygons[last], ring)
+	}
+	return polygons
+}
+
+func isClockwise(ring []spatialPoint) bool {
+	area := 0.0
+	for index := 0; index+1 < len(ring); index++ {
+		area += (ring[index+1].x - ring[index].x) * (ring[index+1].y + ring[index].y)
+	}
+	return area > 0
+}
+
+func wktPoints(points []spatialPoint) string {
+	coordinates := make([]string, len(points))
+	for index, point := range points {
+		coordinates[index] = formatWktNumber(point.x) + ` ` + formatWktNumber(point.y)
+	}
+	return `(` + strings.Join(coordinates, `, `) + `)`
+}
+
+func wktList(parts [][]spatialPoint) string {
+	wktParts := make([]string, len(parts))
+	for index, part := range parts {
+		wktParts[index] = wktPoints(part)
+	}
+	return `(` + strings.Join(wktParts, `, `) + `)`
+}
+
+func formatWktNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,106 @@
+package util_test
+
+import (
+	"encoding/binary"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/tlarsendataguy/goalteryx/sdk"
+	"github.com/tlarsendataguy/graphyx/util"
+	"math"
+	"strings"
+	"testing"
+)
+
+type shapeWriter struct {
+	bytes []byte
+}
+
+func (w *shapeWriter) int32(value int) *shapeWriter {
+	w.bytes = binary.LittleEndian.AppendUint32(w.bytes, uint32(int32(value)))
+	return w
+}
+
+func (w *shapeWriter) points(coordinates ...float64) *shapeWriter {
+	for _, coordinate := range coordinates {
+		w.bytes = binary.LittleEndian.AppendUint64(w.bytes, math.Float64bits(coordinate))
+	}
+	return w
+}
+
+func (w *shapeWriter) box() *shapeWriter {
+	return w.points(0, 0, 0, 0)
+}
+
+func TestSpatialObjPoint(t *testing.T) {
+	blob := (&shapeWriter{}).int32(1).points(-122.5, 45.25).bytes
+	point, err := util.SpatialObjToPoint(blob)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := (dbtype.Point2D{X: -122.5, Y: 45.25, SpatialRefId: 4326}); point != expected {
+		t.Fatalf(`expected %v but got %v`, expected, point)
+	}
+	wkt, err := util.SpatialObjToWKT(blob)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := `POINT (-122.5 45.25)`; wkt != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, wkt)
+	}
+}
+
+func TestSpatialObjPolyLine(t *testing.T) {
+	blob := (&shapeWriter{}).int32(3).box().int32(2).int32(4).int32(0).int32(2).points(0, 0, 1, 1, 2, 2, 3, 3).bytes
+	wkt, err := util.SpatialObjToWKT(blob)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := `MULTILINESTRING ((0 0, 1 1), (2 2, 3 3))`; wkt != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, wkt)
+	}
+	_, err = util.SpatialObjToPoint(blob)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestSpatialObjPolygonWithHole(t *testing.T) {
+	blob := (&shapeWriter{}).int32(5).box().int32(2).int32(10).int32(0).int32(5).
+		points(0, 0, 0, 10, 10, 10, 10, 0, 0, 0).
+		points(2, 2, 4, 2, 4, 4, 2, 4, 2, 2).bytes
+	wkt, err := util.SpatialObjToWKT(blob)
+	if err != nil {
+		t.Fatalf(`expected no error but got: %v`, err.Error())
+	}
+	if expected := `POLYGON ((0 0, 0 10, 10 10, 10 0, 0 0), (2 2, 4 2, 4 4, 2 4, 2 2))`; wkt != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, wkt)
+	}
+}
+
+func TestTruncatedSpatialObj(t *testing.T) {
+	blob := (&shapeWriter{}).int32(3).box().int32(1).int32(5).int32(0).points(0, 0).bytes
+	_, err := util.SpatialObjToWKT(blob)
+	if err == nil {
+		t.Fatalf(`expected an error but got none`)
+	}
+}
+
+func TestUnsupportedFieldTypeErrorNamesType(t *testing.T) {
+	err := &util.UnsupportedFieldTypeError{Field: `Start`, Type: `Unknown`}
+	if expected := `field Start has type 'Unknown', which cannot be sent to Neo4j`; err.Error() != expected {
+		t.Fatalf(`expected '%v' but got '%v'`, expected, err.Error())
+	}
+}
+
+func TestUnsupportedTimeFieldIsExplained(t *testing.T) {
+	err := &util.UnsupportedFieldTypeError{Field: `Start`, Type: `Time`}
+	if !strings.Contains(err.Error(), `cannot read Time fields`) {
+		t.Fatalf(`expected an error explaining Time fields but got %v`, err.Error())
+	}
+}
+
+func TestEmptyLayoutIsReported(t *testing.T) {
+	_, err := util.FindFieldAndGenerateCopier(`Start`, sdk.IncomingRecordInfo{})
+	if err == nil || !strings.Contains(err.Error(), `the incoming record has no fields`) || strings.Contains(err.Error(), `Time`) {
+		t.Fatalf(`expected an error saying the record has no fields but got %v`, err)
+	}
+}